@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rasalas/envlint/internal/diff"
+	"github.com/rasalas/envlint/internal/env"
+	"github.com/rasalas/envlint/internal/lint"
+	"github.com/rasalas/envlint/internal/term"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dryRunFlag bool
+	unsafeFlag bool
+)
+
+func init() {
+	fixCmd := &cobra.Command{
+		Use:   "fix",
+		Short: "Apply automatic fixes to env files",
+		Long:  "Add missing keys, remove duplicates and fix the export prefix in place, keeping comments and layout. --unsafe also applies fixes that change values.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runFix()
+		},
+	}
+	fixCmd.Flags().BoolVar(&dryRunFlag, "dry-run", false, "Show a unified diff instead of writing the files")
+	fixCmd.Flags().BoolVar(&unsafeFlag, "unsafe", false, "Also apply fixes that change values")
+	rootCmd.AddCommand(fixCmd)
+}
+
+func runFix() error {
+	setup, err := loadSetup()
+	if err != nil {
+		return err
+	}
+
+	for _, envPath := range setup.envPaths {
+		result, err := setup.check(envPath)
+		if err != nil {
+			return err
+		}
+		fixed, err := fixFile(setup, envPath, result, unsafeFlag, dryRunFlag)
+		if err != nil {
+			return err
+		}
+		if dryRunFlag {
+			continue
+		}
+
+		term.FileTitle(envPath)
+		if len(fixed) == 0 {
+			term.Header("Nothing to fix")
+		} else {
+			term.Header("Fixed")
+			listed := make(map[string]bool)
+			for _, issue := range fixed {
+				// Duplicates are fixed together; list their key once
+				id := issue.Rule + " " + issue.Key
+				if listed[id] {
+					continue
+				}
+				listed[id] = true
+				term.Pass(issue.Key + " " + term.Dim + "(" + issue.Rule + ")" + term.Reset)
+			}
+		}
+
+		after, err := setup.check(envPath)
+		if err != nil {
+			return err
+		}
+		left := after.ForFile(envPath)
+		fmt.Fprintln(term.W)
+		if n := left.FixableCount(true); n > 0 && !unsafeFlag {
+			term.Info(fmt.Sprintf("%d more fixable with --unsafe", n))
+		}
+		if n := len(left.Issues); n > 0 {
+			term.Info(fmt.Sprintf("%d issue(s) left, run envlint for details", n))
+		}
+		fmt.Fprintln(term.W)
+	}
+	return nil
+}
+
+// fixFile applies the fixes of result to the env file at path and returns
+// the fixed issues. With dryRun the changes are printed as a unified diff
+// instead of written.
+func fixFile(setup *lintSetup, path string, result lint.Result, unsafe, dryRun bool) ([]lint.Issue, error) {
+	doc, err := env.ReadDocument(path, setup.dialect)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return nil, &exitError{code: 2}
+	}
+	before := doc.String()
+	fixed := lint.Fix(doc, path, setup.exampleFile, result.Issues, setup.opts, unsafe)
+	after := doc.String()
+	if after == before {
+		return fixed, nil
+	}
+
+	if dryRun {
+		fmt.Fprint(term.W, diff.Unified(path, path+" (fixed)", before, after))
+		return fixed, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, []byte(after), info.Mode().Perm()); err != nil {
+		return nil, fmt.Errorf("cannot write %s: %w", path, err)
+	}
+	return fixed, nil
+}
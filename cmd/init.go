@@ -3,24 +3,42 @@ package cmd
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
+	"regexp"
 	"strings"
 
+	"github.com/rasalas/envlint/internal/config"
 	"github.com/rasalas/envlint/internal/env"
+	"github.com/rasalas/envlint/internal/lint"
+	"github.com/rasalas/envlint/internal/secret"
 	"github.com/rasalas/envlint/internal/term"
 	"github.com/spf13/cobra"
 )
 
+var (
+	forceFlag       bool
+	interactiveFlag bool
+)
+
 func init() {
-	rootCmd.AddCommand(&cobra.Command{
+	initCmd := &cobra.Command{
 		Use:   "init",
 		Short: "Generate .env.example from existing .env",
+		Long:  "Generate .env.example from existing .env. Values that look like secrets are left empty, safe defaults are kept, and every key whose type can be inferred gets a @type annotation.",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runInit()
 		},
-	})
+	}
+	initCmd.Flags().BoolVar(&forceFlag, "force", false, "Overwrite an existing example file")
+	initCmd.Flags().BoolVar(&interactiveFlag, "interactive", false, "Confirm each key before writing it")
+	rootCmd.AddCommand(initCmd)
 }
 
+// plainWord matches short values such as "info" or "localhost" that are
+// kept as defaults.
+var plainWord = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_.-]{0,31}$`)
+
 func runInit() error {
 	envPath := ".env"
 	if envFlag != "" {
@@ -37,65 +55,133 @@ func runInit() error {
 	}
 
 	// Check if .env.example already exists
-	if _, err := os.Stat(examplePath); err == nil {
-		return fmt.Errorf("%s already exists — remove it first or use a different name", examplePath)
+	if _, err := os.Stat(examplePath); err == nil && !forceFlag {
+		return fmt.Errorf("%s already exists — remove it first or use --force to overwrite it", examplePath)
 	}
 
-	// Read the original .env file line by line and strip values
-	in, err := os.Open(envPath)
+	// Read values the way the configured dialect does
+	cfg := config.Default()
+	if loaded, err := config.Load(); err == nil {
+		cfg = loaded
+	}
+	dialect, err := configDialect(cfg)
 	if err != nil {
 		return err
 	}
-	defer in.Close()
 
-	entries, err := env.ParseFile(envPath)
+	// Keep comments, blank lines and layout; only values and inline
+	// comments change
+	doc, err := env.ReadDocument(envPath, dialect)
 	if err != nil {
 		return err
 	}
-	entryMap := env.ParseEntries(entries)
+	// Suppressions of the env file would only be unused in the example
+	doc.StripDirectives()
 
-	var out strings.Builder
-	scanner := bufio.NewScanner(in)
-	for scanner.Scan() {
-		line := scanner.Text()
-		trimmed := strings.TrimSpace(line)
+	var in *bufio.Reader
+	if interactiveFlag {
+		in = bufio.NewReader(os.Stdin)
+		fmt.Fprintln(term.W)
+	}
 
-		// Pass through empty lines and comments
-		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
-			out.WriteString(line)
-			out.WriteString("\n")
+	seen := make(map[string]bool)
+	keys, kept, annotated := 0, 0, 0
+	for _, e := range doc.Entries() {
+		if seen[e.Key] {
 			continue
 		}
+		seen[e.Key] = true
 
-		eqIdx := strings.IndexByte(trimmed, '=')
-		if eqIdx < 0 {
-			out.WriteString(line)
-			out.WriteString("\n")
-			continue
+		value := ""
+		if safeDefault(e) {
+			value = e.Value
 		}
-
-		key := strings.TrimSpace(trimmed[:eqIdx])
-		entry, exists := entryMap[key]
-
-		// Keep comments but strip values
-		comment := ""
-		if exists && entry.Comment != "" {
-			comment = " # " + entry.Comment
+		annotation := ""
+		if typ := lint.InferType(e.Key, e.Value); typ != "" && !e.IsRef && e.Annotations["type"] == "" {
+			annotation = "@type=" + typ
 		}
 
-		out.WriteString(key + "=" + comment + "\n")
-	}
+		if in != nil {
+			answer, err := confirmKey(in, e.Key, value, annotation)
+			if err != nil {
+				return err
+			}
+			switch answer {
+			case "n":
+				doc.Delete(e.Key)
+				continue
+			case "e":
+				value = ""
+			}
+		}
 
-	if err := scanner.Err(); err != nil {
-		return err
+		doc.Set(e.Key, value)
+		if annotation != "" {
+			doc.Annotate(e.Key, annotation)
+			annotated++
+		}
+		if value != "" {
+			kept++
+		}
+		keys++
 	}
 
-	if err := os.WriteFile(examplePath, []byte(out.String()), 0644); err != nil {
+	if err := os.WriteFile(examplePath, []byte(doc.String()), 0644); err != nil {
 		return err
 	}
 
-	fmt.Fprintf(term.W, "\n  %s✓%s Generated %s from %s (%d keys)\n\n",
-		term.Green, term.Reset, examplePath, envPath, len(entries))
+	fmt.Fprintf(term.W, "\n  %s✓%s Generated %s from %s (%d keys, %d defaults kept, %d annotated)\n\n",
+		term.Green, term.Reset, examplePath, envPath, keys, kept, annotated)
 
 	return nil
 }
+
+// safeDefault reports whether the value of e can be kept in the example:
+// it must not look like a secret and be a number, bool, duration, URL
+// without credentials, reference or short plain word.
+func safeDefault(e env.Entry) bool {
+	val := strings.TrimSpace(e.Value)
+	if val == "" || secret.LooksSecret(e.Key, val) {
+		return false
+	}
+	if e.IsRef {
+		return true
+	}
+	switch lint.InferType(e.Key, val) {
+	case "port", "int", "float", "bool", "duration", "url":
+		return true
+	case "":
+		return plainWord.MatchString(val)
+	}
+	return false
+}
+
+// confirmKey asks whether to write key as proposed. It returns "y" to
+// keep the proposal, "e" to write the key with an empty value and "n" to
+// leave it out.
+func confirmKey(in *bufio.Reader, key, value, annotation string) (string, error) {
+	line := key + "=" + value
+	if annotation != "" {
+		line += " " + term.Dim + "# " + annotation + term.Reset
+	}
+	for {
+		fmt.Fprintf(term.W, "  %s  %s[Y/n/e]%s ", line, term.Dim, term.Reset)
+		answer, err := in.ReadString('\n')
+		if err == io.EOF && answer == "" {
+			// End of input accepts the remaining proposals
+			fmt.Fprintln(term.W)
+			return "y", nil
+		}
+		if err != nil && err != io.EOF {
+			return "", err
+		}
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "", "y", "yes":
+			return "y", nil
+		case "n", "no":
+			return "n", nil
+		case "e", "empty":
+			return "e", nil
+		}
+	}
+}
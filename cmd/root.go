@@ -1,38 +1,55 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
 
 	"github.com/rasalas/envlint/internal/config"
 	"github.com/rasalas/envlint/internal/env"
+	"github.com/rasalas/envlint/internal/gitcheck"
 	"github.com/rasalas/envlint/internal/lint"
+	"github.com/rasalas/envlint/internal/report"
 	"github.com/rasalas/envlint/internal/term"
 	"github.com/spf13/cobra"
 )
 
 var (
-	exampleFlag string
-	envFlag     string
-	strictFlag  bool
-	formatFlag  string
-	quietFlag   bool
+	exampleFlag  string
+	envFlag      string
+	strictFlag   bool
+	formatFlag   string
+	templateFlag string
+	quietFlag    bool
+
+	showSuppressedFlag bool
+	baselineFlag       string
+	writeBaselineFlag  string
+	fixFlag            bool
 )
 
 func init() {
 	rootCmd.PersistentFlags().StringVar(&exampleFlag, "example", "", "Path to example env file (default: .env.example)")
-	rootCmd.PersistentFlags().StringVar(&envFlag, "env", "", "Path to env file to check (default: .env)")
+	rootCmd.PersistentFlags().StringVar(&envFlag, "env", "", "Path to env file to check (default: envFiles from config)")
 	rootCmd.Flags().BoolVar(&strictFlag, "strict", false, "Treat warnings as errors")
-	rootCmd.Flags().StringVar(&formatFlag, "format", "text", "Output format: text or json")
+	rootCmd.Flags().StringVar(&formatFlag, "format", "text", "Output format: text, json, markdown, sarif, github, gitlab, junit, checkstyle or template")
+	rootCmd.Flags().StringVar(&templateFlag, "template", "", "Go template file, or inline template text, for --format template")
 	rootCmd.Flags().BoolVar(&quietFlag, "quiet", false, "Only show errors")
+	rootCmd.Flags().BoolVar(&showSuppressedFlag, "show-suppressed", false, "List issues silenced by envlint-disable comments")
+	rootCmd.Flags().StringVar(&baselineFlag, "baseline", "", "Only report issues not in this baseline file (default: baseline from config)")
+	rootCmd.Flags().StringVar(&writeBaselineFlag, "write-baseline", "", "Record the current issues in a baseline file and exit")
+	rootCmd.Flags().BoolVar(&fixFlag, "fix", false, "Apply safe fixes to the env files before reporting")
 }
 
 var rootCmd = &cobra.Command{
-	Use:   "envlint",
-	Short: "Validate .env files against .env.example",
-	Long:  "Check for missing keys, value formats, empty required fields, and more.",
-	RunE:  runLint,
+	Use:           "envlint",
+	Short:         "Validate .env files against .env.example",
+	Long:          "Check for missing keys, value formats, empty required fields, and more.",
+	RunE:          runLint,
 	SilenceUsage:  true,
 	SilenceErrors: true,
 }
@@ -56,7 +73,20 @@ func (e *exitError) Error() string {
 	return fmt.Sprintf("exit %d", e.code)
 }
 
-func runLint(cmd *cobra.Command, args []string) error {
+// lintSetup is what every linting command needs: the resolved files, the
+// dialect and the linter options.
+type lintSetup struct {
+	cfg         config.Config
+	examplePath string
+	envPaths    []string
+	dialect     env.Dialect
+	exampleFile env.File
+	opts        lint.Options
+}
+
+// loadSetup loads the config, resolves the files to lint and parses the
+// example. Errors are printed and returned as exit code 2.
+func loadSetup() (*lintSetup, error) {
 	// Load config
 	cfg := config.Default()
 	if loaded, err := config.Load(); err == nil {
@@ -68,111 +98,526 @@ func runLint(cmd *cobra.Command, args []string) error {
 	if exampleFlag != "" {
 		examplePath = exampleFlag
 	}
-	envPath := ".env"
-	if len(cfg.EnvFiles) > 0 {
-		envPath = cfg.EnvFiles[0]
+	envPaths := []string{envFlag}
+	if envFlag == "" {
+		resolved, err := config.ResolveEnvFiles(cfg.EnvFiles, examplePath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return nil, &exitError{code: 2}
+		}
+		if len(resolved) == 0 {
+			fmt.Fprintf(os.Stderr, "Error: no env files match %v\n", cfg.EnvFiles)
+			return nil, &exitError{code: 2}
+		}
+		envPaths = resolved
 	}
-	if envFlag != "" {
-		envPath = envFlag
+
+	dialect, err := configDialect(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return nil, &exitError{code: 2}
 	}
 
-	// Parse files
-	exampleEntries, err := env.ParseFile(examplePath)
+	// Parse example once; it is shared by every env file
+	exampleFile, err := env.ParseDialect(examplePath, dialect)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		return &exitError{code: 2}
+		return nil, &exitError{code: 2}
 	}
 
-	envEntries, err := env.ParseFile(envPath)
+	keys, err := keySpecs(cfg.Keys)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		return &exitError{code: 2}
+		return nil, &exitError{code: 2}
+	}
+	severity, err := ruleSeverities(cfg.Rules.Severity)
+	if err == nil {
+		err = checkStrictSwitches(cfg.Rules, severity)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return nil, &exitError{code: 2}
+	}
+	if wins := cfg.Rules.DuplicateWins; wins != "first" && wins != "last" {
+		fmt.Fprintf(os.Stderr, "Error: invalid duplicateWins %q in [rules] (expected first or last)\n", wins)
+		return nil, &exitError{code: 2}
+	}
+	switch cfg.Rules.ExportPrefix {
+	case "", "require", "forbid", "consistent":
+	default:
+		fmt.Fprintf(os.Stderr, "Error: invalid exportPrefix %q in [rules] (expected require, forbid or consistent)\n", cfg.Rules.ExportPrefix)
+		return nil, &exitError{code: 2}
+	}
+	secretAllow, err := compilePatterns(cfg.Rules.Secrets.Allow, "", "[rules.secrets] allow")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return nil, &exitError{code: 2}
+	}
+	placeholders, err := compilePatterns(cfg.Rules.Placeholders.Patterns, "(?i)^(?:%s)$", "[rules.placeholders]")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return nil, &exitError{code: 2}
 	}
 
 	// Build linter options from config
 	opts := lint.Options{
-		Strict:     strictFlag || cfg.Rules.RequireAll,
-		NoExtra:    cfg.Rules.NoExtra,
-		StrictURLs: cfg.Rules.StrictURLs,
-		StrictPorts: cfg.Rules.StrictPorts,
+		Strict:       strictFlag || cfg.Rules.RequireAll,
+		NoExtra:      cfg.Rules.NoExtra,
+		StrictURLs:   cfg.Rules.StrictURLs,
+		StrictPorts:  cfg.Rules.StrictPorts,
 		RequiredKeys: cfg.Rules.Required.Keys,
 		IgnoreKeys:   cfg.Rules.Ignore.Keys,
+		FirstWins:    cfg.Rules.DuplicateWins == "first",
+		ExportPrefix: cfg.Rules.ExportPrefix,
+		Keys:         keys,
+		Severity:     severity,
+		SecretKeys:   cfg.Rules.Secrets.Keys,
+		SecretAllow:  secretAllow,
+		Placeholders: placeholders,
 	}
+	if cfg.Rules.ProcessEnv {
+		opts.Lookup = os.LookupEnv
+	}
+
+	return &lintSetup{
+		cfg:         cfg,
+		examplePath: examplePath,
+		envPaths:    envPaths,
+		dialect:     dialect,
+		exampleFile: exampleFile,
+		opts:        opts,
+	}, nil
+}
 
-	// Run linter
-	result := lint.Check(exampleEntries, envEntries, opts)
+// configDialect returns the dialect named in the config.
+func configDialect(cfg config.Config) (env.Dialect, error) {
+	dialect, ok := env.LookupDialect(cfg.Dialect)
+	if !ok {
+		return env.Dialect{}, fmt.Errorf("unknown dialect %q (expected one of: %s)",
+			cfg.Dialect, strings.Join(env.DialectNames(), ", "))
+	}
+	return dialect, nil
+}
 
-	// Promote warnings to errors in strict mode
-	if strictFlag {
-		result.PromoteWarnings()
+// check parses and lints one env file.
+func (s *lintSetup) check(envPath string) (lint.Result, error) {
+	envFile, err := env.ParseDialect(envPath, s.dialect)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return lint.Result{}, &exitError{code: 2}
+	}
+	return lint.CheckFiles(s.exampleFile, envFile, s.opts), nil
+}
+
+func runLint(cmd *cobra.Command, args []string) error {
+	format := formatFlag
+	if templateFlag != "" && !cmd.Flags().Changed("format") {
+		format = "template"
+	}
+	reporter, err := reporterFor(format)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return &exitError{code: 2}
+	}
+	setup, err := loadSetup()
+	if err != nil {
+		return err
+	}
+	cfg, examplePath, envPaths := setup.cfg, setup.examplePath, setup.envPaths
+
+	baselinePath := cfg.Baseline
+	if baselineFlag != "" {
+		baselinePath = baselineFlag
+	}
+	var baseline *lint.Baseline
+	if baselinePath != "" && writeBaselineFlag == "" {
+		b, err := lint.ReadBaseline(baselinePath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return &exitError{code: 2}
+		}
+		baseline = &b
+	}
+
+	// Run linter per env file
+	var combined lint.Result
+	results := make([]lint.Result, 0, len(envPaths))
+	for _, envPath := range envPaths {
+		result, err := setup.check(envPath)
+		if err != nil {
+			return err
+		}
+		if fixFlag {
+			// Fix safe issues, then report what is left
+			if _, err := fixFile(setup, envPath, result, false, false); err != nil {
+				return err
+			}
+			if result, err = setup.check(envPath); err != nil {
+				return err
+			}
+		}
+		if baseline != nil {
+			baseline.Apply(&result, envPath, examplePath)
+		}
+
+		// Promote warnings to errors in strict mode
+		if strictFlag {
+			result.PromoteWarnings()
+		}
+
+		results = append(results, result)
+		combined.Merge(result)
+	}
+
+	if writeBaselineFlag != "" {
+		b := lint.NewBaseline(combined)
+		if err := lint.WriteBaseline(writeBaselineFlag, b); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return &exitError{code: 2}
+		}
+		fmt.Fprintf(term.W, "\n  %s✓%s Wrote baseline %s (%d issue(s) in %d file(s))\n\n",
+			term.Green, term.Reset, writeBaselineFlag, len(combined.Issues), baselineFiles(b))
+		return nil
 	}
 
 	// Output
-	switch formatFlag {
-	case "json":
-		return outputJSON(result)
-	default:
-		outputText(result, envPath, examplePath)
+	run := report.Run{
+		Result:         combined,
+		Example:        examplePath,
+		ExampleKeys:    exampleKeys(setup),
+		Root:           gitcheck.RepoRoot(),
+		ShowSuppressed: showSuppressedFlag,
+	}
+	for i, result := range results {
+		run.Files = append(run.Files, report.FileResult{Path: envPaths[i], Result: result})
+	}
+	if err := reporter.Report(term.W, run); err != nil {
+		return err
 	}
 
-	if result.ErrorCount() > 0 {
+	if combined.ErrorCount() > 0 {
 		return &exitError{code: 1}
 	}
 	return nil
 }
 
-func outputJSON(result lint.Result) error {
-	enc := json.NewEncoder(os.Stdout)
-	enc.SetIndent("", "  ")
-	return enc.Encode(result.ToJSON())
+// baselineFiles counts the files that have entries in b.
+func baselineFiles(b lint.Baseline) int {
+	files := make(map[string]bool)
+	for _, e := range b.Issues {
+		files[e.File] = true
+	}
+	return len(files)
+}
+
+// keySpecs converts the [keys] config tables into lint specs.
+func keySpecs(tables map[string]config.KeySchema) (map[string]lint.KeySpec, error) {
+	specs := make(map[string]lint.KeySpec, len(tables))
+	for key, t := range tables {
+		spec := lint.KeySpec{
+			Type:        t.Type,
+			Enum:        t.Enum,
+			Pattern:     t.Pattern,
+			Min:         t.Min,
+			Max:         t.Max,
+			MinLength:   t.MinLength,
+			MaxLength:   t.MaxLength,
+			Required:    t.Required,
+			Secret:      t.Secret,
+			Deprecated:  t.Deprecated,
+			Description: t.Description,
+		}
+		if err := spec.Validate(); err != nil {
+			return nil, fmt.Errorf("[keys.%s] in config: %w", key, err)
+		}
+		specs[key] = spec
+	}
+	return specs, nil
 }
 
-func outputText(result lint.Result, envPath, examplePath string) {
+// ruleSeverities converts the [rules.severity] table, rejecting unknown
+// rule IDs and levels.
+func ruleSeverities(table map[string]string) (map[string]lint.Severity, error) {
+	severity := make(map[string]lint.Severity, len(table))
+	for id, level := range table {
+		if _, ok := lint.LookupRule(id); !ok {
+			return nil, fmt.Errorf("unknown rule %q in [rules.severity] (see envlint rules)", id)
+		}
+		sev, ok := lint.ParseSeverity(level)
+		if !ok {
+			return nil, fmt.Errorf("invalid severity %q for %s (expected error, warning, info or off)", level, id)
+		}
+		severity[id] = sev
+	}
+	return severity, nil
+}
+
+// checkStrictSwitches rejects strictUrls or strictPorts set to false
+// together with a level other than off for the same rule in
+// [rules.severity], which would ask for the name-based checks both off
+// and on.
+func checkStrictSwitches(rules config.Rules, severity map[string]lint.Severity) error {
+	for _, s := range []struct {
+		name, rule string
+		enabled    bool
+	}{
+		{"strictUrls", "invalid-url", rules.StrictURLs},
+		{"strictPorts", "invalid-port", rules.StrictPorts},
+	} {
+		if sev, ok := severity[s.rule]; ok && !s.enabled && sev != lint.SeverityOff {
+			return fmt.Errorf("%s = false conflicts with %s = %q in [rules.severity] (use %s = \"off\" or drop %s)",
+				s.name, s.rule, sev, s.rule, s.name)
+		}
+	}
+	return nil
+}
+
+// compilePatterns compiles the regular expressions of a config table,
+// each wrapped in format when it is not empty.
+func compilePatterns(patterns []string, format, table string) ([]*regexp.Regexp, error) {
+	var res []*regexp.Regexp
+	for _, p := range patterns {
+		expr := p
+		if format != "" {
+			expr = fmt.Sprintf(format, p)
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q in %s", p, table)
+		}
+		res = append(res, re)
+	}
+	return res, nil
+}
+
+// reporterFor returns the reporter of a --format value.
+func reporterFor(format string) (report.Reporter, error) {
+	switch {
+	case templateFlag != "" && format != "template":
+		return nil, fmt.Errorf("--template needs --format template, not %s", format)
+	case format == "text":
+		return textReporter{}, nil
+	case format == "template":
+		return loadTemplate(templateFlag)
+	}
+	if r, ok := report.Lookup(format); ok {
+		return r, nil
+	}
+	formats := append([]string{"text", "template"}, report.Formats()...)
+	return nil, fmt.Errorf("unknown format %q (expected one of: %s)", format, strings.Join(formats, ", "))
+}
+
+// loadTemplate parses the --template value: inline template text if it
+// contains "{{", otherwise the path of a template file.
+func loadTemplate(value string) (report.Reporter, error) {
+	if value == "" {
+		return nil, fmt.Errorf("--format template needs --template with a file or inline template")
+	}
+	name, text := "inline", value
+	if !strings.Contains(value, "{{") {
+		data, err := os.ReadFile(value)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read template: %w", err)
+		}
+		name, text = filepath.Base(value), string(data)
+	}
+	t, err := report.NewTemplate(name, text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	return t, nil
+}
+
+// exampleKeys returns the keys of the example that are linted, in order.
+func exampleKeys(setup *lintSetup) []string {
+	var keys []string
+	for _, e := range setup.exampleFile.Entries {
+		if !slices.Contains(keys, e.Key) && !slices.Contains(setup.opts.IgnoreKeys, e.Key) {
+			keys = append(keys, e.Key)
+		}
+	}
+	return keys
+}
+
+// textReporter writes the grouped, colored terminal output.
+type textReporter struct{}
+
+func (textReporter) Report(w io.Writer, run report.Run) error {
+	defer func(prev io.Writer) { term.W = prev }(term.W)
+	term.W = w
+
+	for _, f := range run.Files {
+		outputText(f.Result.ForFile(f.Path), f.Path, run.Example, run.ShowSuppressed)
+	}
+	if exampleIssues := run.Result.ForFile(run.Example); len(exampleIssues.Issues) > 0 || len(exampleIssues.Fixed) > 0 ||
+		(run.ShowSuppressed && len(exampleIssues.Suppressed) > 0) {
+		outputExampleText(exampleIssues, run.Example, run.ShowSuppressed)
+	}
+	if len(run.Files) > 1 {
+		term.Total(len(run.Files), run.Result.ErrorCount(), run.Result.WarnCount(), run.Result.InfoCount())
+		fmt.Fprintln(term.W)
+	}
+	return nil
+}
+
+func outputText(result lint.Result, envPath, examplePath string, showSuppressed bool) {
 	term.Title(envPath, examplePath)
 
 	// Group issues by category
 	missing := result.ByRule("missing-key")
 	extra := result.ByRule("extra-key")
+	syntax := result.SyntaxIssues()
 	values := result.ValueIssues()
 
+	outputSyntaxText(syntax)
+
 	if len(missing) > 0 {
 		term.Header("Missing keys")
 		for _, issue := range missing {
-			if issue.Severity == lint.SeverityError {
-				suffix := ""
-				if issue.Detail != "" {
-					suffix = "  " + term.Dim + "(" + issue.Detail + ")" + term.Reset
-				}
-				term.Fail(issue.Key + suffix)
-			} else {
-				term.Warn(issue.Key)
+			suffix := ""
+			if issue.Detail != "" {
+				suffix = "  " + term.Dim + "(" + issue.Detail + ")" + term.Reset
 			}
+			outputIssue(issue, issue.Key+suffix, "")
 		}
 	}
 
-	if len(extra) > 0 && !quietFlag {
+	if len(extra) > 0 && (!quietFlag || countErrors(extra) > 0) {
 		term.Header("Extra keys")
 		for _, issue := range extra {
-			term.Warn(issue.Key)
+			outputIssue(issue, issue.Key, "")
 		}
 	}
 
 	if len(values) > 0 {
 		term.Header("Value problems")
 		for _, issue := range values {
-			if issue.Severity == lint.SeverityError {
-				term.FailDetail(issue.Key, issue.Detail)
-			} else if !quietFlag {
-				term.WarnDetail(issue.Key, issue.Detail)
-			}
+			outputIssue(issue, issueLabel(issue), issue.Detail)
 		}
 	}
 
+	if showSuppressed {
+		outputSuppressedText(result.Suppressed)
+	}
+	outputFixedText(result.Fixed)
+
 	total := result.TotalKeys()
 	valid := total - result.ErrorCount() - result.WarnCount()
 	if valid < 0 {
 		valid = 0
 	}
-	term.Summary(valid, total, result.ErrorCount(), result.WarnCount())
+	term.Summary(valid, total, result.ErrorCount(), result.WarnCount(), result.InfoCount())
+	if n := len(result.Suppressed); n > 0 && !showSuppressed {
+		term.Info(fmt.Sprintf("    %d issue(s) suppressed, --show-suppressed lists them", n))
+	}
+	if n := len(result.Baselined); n > 0 {
+		term.Info(fmt.Sprintf("    %d known issue(s) in baseline", n))
+	}
+	if n := result.FixableCount(false); n > 0 {
+		term.Info(fmt.Sprintf("    %d issue(s) fixable with envlint fix", n))
+	}
+	fmt.Fprintln(term.W)
+}
+
+// issueLabel names the key of an issue, or its line for issues that
+// belong to no key.
+func issueLabel(issue lint.Issue) string {
+	if issue.Key == "" {
+		return fmt.Sprintf("line %d", issue.LineNum)
+	}
+	return issue.Key
+}
+
+// outputFixedText lists baseline entries that no longer occur.
+func outputFixedText(fixed []lint.BaselineEntry) {
+	if len(fixed) == 0 {
+		return
+	}
+	term.Header("Fixed since baseline")
+	for _, e := range fixed {
+		label := e.Rule
+		if e.Key != "" {
+			label = e.Key + " " + term.Dim + "(" + e.Rule + ")" + term.Reset
+		}
+		term.Pass(label)
+	}
+	term.Info("run with --write-baseline to update the baseline")
+}
+
+// outputSuppressedText lists suppressed issues with --show-suppressed.
+func outputSuppressedText(issues []lint.Issue) {
+	if len(issues) == 0 {
+		return
+	}
+	term.Header("Suppressed")
+	for _, issue := range issues {
+		line := issueLabel(issue) + " — " + issue.Rule
+		if issue.Detail != "" {
+			line += ": " + issue.Detail
+		}
+		term.Info(line)
+	}
+}
+
+// outputExampleText prints problems found in the example file itself.
+func outputExampleText(result lint.Result, examplePath string, showSuppressed bool) {
+	term.FileTitle(examplePath)
+	outputSyntaxText(result.SyntaxIssues())
+	if other := result.ValueIssues(); len(other) > 0 {
+		term.Header("Problems")
+		for _, issue := range other {
+			label := issueLabel(issue)
+			if issue.Key != "" {
+				label += " " + term.Dim + fmt.Sprintf("(line %d)", issue.LineNum) + term.Reset
+			}
+			outputIssue(issue, label, issue.Detail)
+		}
+	}
+	if showSuppressed {
+		outputSuppressedText(result.Suppressed)
+	}
+	outputFixedText(result.Fixed)
 	fmt.Fprintln(term.W)
 }
+
+func outputSyntaxText(issues []lint.Issue) {
+	if len(issues) == 0 {
+		return
+	}
+	term.Header("Syntax problems")
+	for _, issue := range issues {
+		label := fmt.Sprintf("line %d:%d", issue.LineNum, issue.Column)
+		if issue.Key != "" {
+			label = issue.Key + " " + term.Dim + "(" + label + ")" + term.Reset
+		}
+		outputIssue(issue, label, issue.Detail)
+	}
+}
+
+// outputIssue prints one issue with the marker of its severity. Quiet mode
+// only prints errors.
+func outputIssue(issue lint.Issue, label, detail string) {
+	switch {
+	case issue.Severity == lint.SeverityError && detail != "":
+		term.FailDetail(label, detail)
+	case issue.Severity == lint.SeverityError:
+		term.Fail(label)
+	case quietFlag:
+	case issue.Severity == lint.SeverityInfo:
+		term.NoteDetail(label, detail)
+	case detail != "":
+		term.WarnDetail(label, detail)
+	default:
+		term.Warn(label)
+	}
+}
+
+func countErrors(issues []lint.Issue) int {
+	n := 0
+	for _, issue := range issues {
+		if issue.Severity == lint.SeverityError {
+			n++
+		}
+	}
+	return n
+}
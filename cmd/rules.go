@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/rasalas/envlint/internal/lint"
+	"github.com/rasalas/envlint/internal/term"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(&cobra.Command{
+		Use:   "rules",
+		Short: "List the available lint rules",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRules()
+		},
+	})
+}
+
+func runRules() error {
+	rules := lint.Rules()
+
+	width := 0
+	for _, r := range rules {
+		width = max(width, len(r.ID()))
+	}
+
+	term.Header("Rules")
+	for _, r := range rules {
+		color := term.Yellow
+		if r.DefaultSeverity() == lint.SeverityError {
+			color = term.Red
+		}
+		fmt.Fprintf(term.W, "  %-*s  %s%-7s%s  %s%s%s\n",
+			width, r.ID(), color, r.DefaultSeverity(), term.Reset, term.Dim, r.Description(), term.Reset)
+	}
+	fmt.Fprintln(term.W)
+	return nil
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/rasalas/envlint/internal/diff"
+	"github.com/rasalas/envlint/internal/env"
+	"github.com/rasalas/envlint/internal/term"
+	"github.com/spf13/cobra"
+)
+
+var pruneFlag bool
+
+func init() {
+	syncCmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Add new keys from env files to .env.example",
+		Long:  "Add keys that env files define but the example lacks, with empty values, next to the keys that share their prefix. The rest of the example is left untouched.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSync()
+		},
+	}
+	syncCmd.Flags().BoolVar(&pruneFlag, "prune", false, "Remove example keys that no env file uses")
+	syncCmd.Flags().BoolVar(&dryRunFlag, "dry-run", false, "Show a unified diff instead of writing the example")
+	rootCmd.AddCommand(syncCmd)
+}
+
+func runSync() error {
+	setup, err := loadSetup()
+	if err != nil {
+		return err
+	}
+	doc, err := env.ReadDocument(setup.examplePath, setup.dialect)
+	if err != nil {
+		return err
+	}
+	before := doc.String()
+
+	// Collect keys in the order the env files define them
+	var added, removed []string
+	used := make(map[string]bool)
+	for _, envPath := range setup.envPaths {
+		envFile, err := env.ParseDialect(envPath, setup.dialect)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return &exitError{code: 2}
+		}
+		for _, e := range envFile.Entries {
+			used[e.Key] = true
+			if doc.Has(e.Key) || slices.Contains(setup.opts.IgnoreKeys, e.Key) {
+				continue
+			}
+			comment := e.Comment
+			if env.IsDirective(comment) {
+				// A suppression for the env file would be unused in the example
+				comment = ""
+			}
+			doc.InsertGrouped(env.Entry{Key: e.Key, Comment: comment, Export: e.Export})
+			added = append(added, e.Key)
+		}
+	}
+
+	if pruneFlag {
+		for _, e := range setup.exampleFile.Entries {
+			if !used[e.Key] && !slices.Contains(removed, e.Key) && !slices.Contains(setup.opts.IgnoreKeys, e.Key) {
+				doc.DeleteWithComments(e.Key)
+				removed = append(removed, e.Key)
+			}
+		}
+	}
+
+	after := doc.String()
+	if dryRunFlag {
+		fmt.Fprint(term.W, diff.Unified(setup.examplePath, setup.examplePath+" (synced)", before, after))
+		return nil
+	}
+
+	term.FileTitle(setup.examplePath)
+	if after == before {
+		term.Header("Already in sync")
+		return nil
+	}
+	if err := os.WriteFile(setup.examplePath, []byte(after), 0644); err != nil {
+		return fmt.Errorf("cannot write %s: %w", setup.examplePath, err)
+	}
+	if len(added) > 0 {
+		term.Header("Added")
+		for _, key := range added {
+			term.Pass(key)
+		}
+	}
+	if len(removed) > 0 {
+		term.Header("Removed")
+		for _, key := range removed {
+			term.Fail(key)
+		}
+	}
+	fmt.Fprintln(term.W)
+	return nil
+}
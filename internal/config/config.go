@@ -2,6 +2,8 @@ package config
 
 import (
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/BurntSushi/toml"
 )
@@ -12,10 +14,38 @@ const defaultConfigFile = ".envlint.toml"
 type Config struct {
 	Example  string   `toml:"example"`
 	EnvFiles []string `toml:"envFiles"`
-	Rules    Rules    `toml:"rules"`
+	// Dialect names the dotenv loader whose quoting and escape rules
+	// are used to read values: default, godotenv, python-dotenv, node
+	// or compose.
+	Dialect string `toml:"dialect"`
+	// Baseline is a file of known issues written by --write-baseline;
+	// only issues it does not list are reported.
+	Baseline string `toml:"baseline"`
+	Rules    Rules  `toml:"rules"`
+
+	// Keys describes the expected values of individual keys. It takes
+	// precedence over annotations in the example file.
+	Keys map[string]KeySchema `toml:"keys"`
+}
+
+// KeySchema is a [keys.NAME] table.
+type KeySchema struct {
+	Type        string   `toml:"type"`
+	Required    bool     `toml:"required"`
+	Secret      bool     `toml:"secret"`
+	Pattern     string   `toml:"pattern"`
+	Enum        []string `toml:"enum"`
+	Min         *float64 `toml:"min"`
+	Max         *float64 `toml:"max"`
+	MinLength   *int     `toml:"minLength"`
+	MaxLength   *int     `toml:"maxLength"`
+	Deprecated  bool     `toml:"deprecated"`
+	Description string   `toml:"description"`
 }
 
-// Rules holds validation rule settings.
+// Rules holds validation rule settings. StrictURLs and StrictPorts predate
+// Severity, which should be used instead; setting one to false conflicts
+// with a level other than "off" for its rule.
 type Rules struct {
 	RequireAll  bool     `toml:"requireAll"`
 	NoExtra     bool     `toml:"noExtra"`
@@ -23,6 +53,41 @@ type Rules struct {
 	StrictPorts bool     `toml:"strictPorts"`
 	Required    KeyList  `toml:"required"`
 	Ignore      KeyList  `toml:"ignore"`
+
+	// DuplicateWins is "last" or "first": which definition of a
+	// duplicated key the app's dotenv loader uses.
+	DuplicateWins string `toml:"duplicateWins"`
+
+	// ExportPrefix is "require", "forbid" or "consistent"; empty allows
+	// both "export KEY=value" and "KEY=value".
+	ExportPrefix string `toml:"exportPrefix"`
+
+	// ProcessEnv resolves references to variables the env file does not
+	// define from the process environment.
+	ProcessEnv bool `toml:"processEnv"`
+
+	// Severity maps rule IDs to "error", "warning", "info" or "off".
+	Severity map[string]string `toml:"severity"`
+
+	// Secrets allowlists example values that secret-in-example reports.
+	Secrets SecretRules `toml:"secrets"`
+
+	// Placeholders adds to the values placeholder-value reports.
+	Placeholders PatternList `toml:"placeholders"`
+}
+
+// SecretRules is the [rules.secrets] table.
+type SecretRules struct {
+	// Keys are never checked for secrets.
+	Keys []string `toml:"keys"`
+	// Allow holds regular expressions for values that are known to be
+	// safe, such as test keys.
+	Allow []string `toml:"allow"`
+}
+
+// PatternList holds a list of regular expressions.
+type PatternList struct {
+	Patterns []string `toml:"patterns"`
 }
 
 // KeyList holds a list of key names.
@@ -36,8 +101,9 @@ func Default() Config {
 		Example:  ".env.example",
 		EnvFiles: []string{".env"},
 		Rules: Rules{
-			StrictURLs:  true,
-			StrictPorts: true,
+			StrictURLs:    true,
+			StrictPorts:   true,
+			DuplicateWins: "last",
 		},
 	}
 }
@@ -62,3 +128,50 @@ func LoadFrom(path string) (Config, error) {
 
 	return cfg, nil
 }
+
+// ResolveEnvFiles expands glob patterns in patterns and drops the example file.
+// Literal paths are kept even if they do not exist so callers can report them.
+// Paths are compared in absolute form, so relative and absolute spellings
+// of the same file match.
+func ResolveEnvFiles(patterns []string, example string) ([]string, error) {
+	exclude := absPath(example)
+	seen := make(map[string]bool)
+	var files []string
+
+	add := func(path string) {
+		abs := absPath(path)
+		if abs == exclude || seen[abs] {
+			return
+		}
+		seen[abs] = true
+		files = append(files, path)
+	}
+
+	for _, pattern := range patterns {
+		if !strings.ContainsAny(pattern, "*?[") {
+			add(pattern)
+			continue
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range matches {
+			if info, err := os.Stat(m); err == nil && !info.IsDir() {
+				add(m)
+			}
+		}
+	}
+
+	return files, nil
+}
+
+// absPath returns the absolute form of path, or the cleaned path if it has
+// none.
+func absPath(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.Clean(path)
+	}
+	return abs
+}
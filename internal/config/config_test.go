@@ -25,17 +25,42 @@ func TestLoadFrom(t *testing.T) {
 	content := `
 example = ".env.production"
 envFiles = [".env", ".env.local"]
+dialect = "godotenv"
 
 [rules]
 requireAll = true
 noExtra = true
 strictUrls = false
+duplicateWins = "first"
 
 [rules.required]
 keys = ["API_KEY", "DB_URL"]
 
 [rules.ignore]
 keys = ["DEBUG"]
+
+[rules.severity]
+invalid-email = "error"
+extra-key = "off"
+
+[rules.secrets]
+keys = ["JWT_PUBLIC_KEY"]
+allow = ["^sk_test_"]
+
+[rules.placeholders]
+patterns = ["replace-with-.*"]
+
+[keys.TIMEOUT_MS]
+type = "int"
+min = 100
+max = 60000
+
+[keys.JWT_SECRET]
+secret = true
+
+[keys.LEGACY_TOKEN]
+deprecated = true
+description = "use API_KEY"
 `
 	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
 		t.Fatal(err)
@@ -52,6 +77,9 @@ keys = ["DEBUG"]
 	if len(cfg.EnvFiles) != 2 {
 		t.Fatalf("expected 2 envFiles, got %d", len(cfg.EnvFiles))
 	}
+	if cfg.Dialect != "godotenv" {
+		t.Errorf("expected godotenv dialect, got %q", cfg.Dialect)
+	}
 	if !cfg.Rules.RequireAll {
 		t.Error("expected requireAll to be true")
 	}
@@ -61,12 +89,34 @@ keys = ["DEBUG"]
 	if cfg.Rules.StrictURLs {
 		t.Error("expected strictUrls to be false")
 	}
+	if cfg.Rules.DuplicateWins != "first" {
+		t.Errorf("expected duplicateWins first, got %q", cfg.Rules.DuplicateWins)
+	}
 	if len(cfg.Rules.Required.Keys) != 2 {
 		t.Errorf("expected 2 required keys, got %d", len(cfg.Rules.Required.Keys))
 	}
 	if len(cfg.Rules.Ignore.Keys) != 1 {
 		t.Errorf("expected 1 ignore key, got %d", len(cfg.Rules.Ignore.Keys))
 	}
+	if cfg.Rules.Severity["invalid-email"] != "error" || cfg.Rules.Severity["extra-key"] != "off" {
+		t.Errorf("unexpected severity table: %v", cfg.Rules.Severity)
+	}
+	if len(cfg.Rules.Secrets.Keys) != 1 || len(cfg.Rules.Secrets.Allow) != 1 {
+		t.Errorf("unexpected secrets table: %+v", cfg.Rules.Secrets)
+	}
+	if len(cfg.Rules.Placeholders.Patterns) != 1 {
+		t.Errorf("unexpected placeholders table: %+v", cfg.Rules.Placeholders)
+	}
+	if !cfg.Keys["JWT_SECRET"].Secret {
+		t.Error("expected JWT_SECRET to be marked secret")
+	}
+	timeout := cfg.Keys["TIMEOUT_MS"]
+	if timeout.Type != "int" || timeout.Min == nil || *timeout.Min != 100 || timeout.Max == nil || *timeout.Max != 60000 {
+		t.Errorf("unexpected TIMEOUT_MS schema: %+v", timeout)
+	}
+	if legacy := cfg.Keys["LEGACY_TOKEN"]; !legacy.Deprecated || legacy.Description != "use API_KEY" {
+		t.Errorf("unexpected LEGACY_TOKEN schema: %+v", legacy)
+	}
 }
 
 func TestLoadFromMissing(t *testing.T) {
@@ -75,3 +125,66 @@ func TestLoadFromMissing(t *testing.T) {
 		t.Error("expected error for nonexistent config")
 	}
 }
+
+func TestResolveEnvFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{".env", ".env.local", ".env.test", ".env.example"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(""), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	example := filepath.Join(dir, ".env.example")
+	patterns := []string{filepath.Join(dir, ".env"), filepath.Join(dir, ".env.*")}
+
+	files, err := ResolveEnvFiles(patterns, example)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 3 {
+		t.Fatalf("expected 3 files, got %v", files)
+	}
+	for _, f := range files {
+		if f == example {
+			t.Errorf("example file should be excluded, got %v", files)
+		}
+	}
+}
+
+func TestResolveEnvFilesMixedPaths(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{".env", ".env.example"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(""), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Chdir(dir)
+
+	tests := []struct {
+		patterns []string
+		example  string
+	}{
+		{[]string{".env*"}, filepath.Join(dir, ".env.example")},
+		{[]string{filepath.Join(dir, ".env*")}, ".env.example"},
+		{[]string{".env", "./.env.example"}, filepath.Join(dir, ".env.example")},
+	}
+	for _, tt := range tests {
+		files, err := ResolveEnvFiles(tt.patterns, tt.example)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(files) != 1 || filepath.Base(files[0]) != ".env" {
+			t.Errorf("ResolveEnvFiles(%v, %s) = %v, expected only .env", tt.patterns, tt.example, files)
+		}
+	}
+}
+
+func TestResolveEnvFilesKeepsLiteralPaths(t *testing.T) {
+	files, err := ResolveEnvFiles([]string{"/nonexistent/.env"}, ".env.example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0] != "/nonexistent/.env" {
+		t.Errorf("expected literal path to be kept, got %v", files)
+	}
+}
@@ -0,0 +1,43 @@
+package diff
+
+import "testing"
+
+func TestUnified(t *testing.T) {
+	a := "A=1\nB=2\nC=3\nD=4\nE=5\nF=6\nG=7\nH=8\nI=9\nJ=10\n"
+	b := "A=1\nB=two\nC=3\nD=4\nE=5\nF=6\nG=7\nH=8\nI=9\nJ=10\nK=11\n"
+
+	want := `--- .env
++++ .env (fixed)
+@@ -1,5 +1,5 @@
+ A=1
+-B=2
++B=two
+ C=3
+ D=4
+ E=5
+@@ -8,3 +8,4 @@
+ H=8
+ I=9
+ J=10
++K=11
+`
+	if got := Unified(".env", ".env (fixed)", a, b); got != want {
+		t.Errorf("unexpected diff:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestUnifiedEdgeCases(t *testing.T) {
+	if got := Unified("a", "b", "X=1\n", "X=1\n"); got != "" {
+		t.Errorf("expected no diff for equal input, got %q", got)
+	}
+
+	want := "--- a\n+++ b\n@@ -0,0 +1 @@\n+X=1\n"
+	if got := Unified("a", "b", "", "X=1\n"); got != want {
+		t.Errorf("unexpected diff from empty file:\n%q\nwant\n%q", got, want)
+	}
+
+	want = "--- a\n+++ b\n@@ -1 +1,2 @@\n-X=1\n\\ No newline at end of file\n+X=1\n+Y=2\n"
+	if got := Unified("a", "b", "X=1", "X=1\nY=2\n"); got != want {
+		t.Errorf("unexpected diff for missing newline:\n%q\nwant\n%q", got, want)
+	}
+}
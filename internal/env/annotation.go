@@ -0,0 +1,59 @@
+package env
+
+import "strings"
+
+// parseAnnotations reads @name=value and bare @name tokens from comment
+// text, e.g. "@type=int @min=1 @max=100" or `@pattern="^sk_ .*$"`.
+// Text that is not an annotation is ignored.
+func parseAnnotations(comment string, into map[string]string) map[string]string {
+	for i := 0; i < len(comment); i++ {
+		if comment[i] != '@' || (i > 0 && comment[i-1] != ' ' && comment[i-1] != '\t') {
+			continue
+		}
+
+		start := i + 1
+		end := start
+		for end < len(comment) && isAnnotationNameByte(comment[end], end == start) {
+			end++
+		}
+		if end == start {
+			continue
+		}
+		name := comment[start:end]
+		value := ""
+
+		if end < len(comment) && comment[end] == '=' {
+			end++
+			if end < len(comment) && comment[end] == '"' {
+				if close := strings.IndexByte(comment[end+1:], '"'); close >= 0 {
+					value = comment[end+1 : end+1+close]
+					end += close + 2
+				}
+			} else {
+				vEnd := end
+				for vEnd < len(comment) && comment[vEnd] != ' ' && comment[vEnd] != '\t' {
+					vEnd++
+				}
+				value = comment[end:vEnd]
+				end = vEnd
+			}
+		}
+
+		if into == nil {
+			into = make(map[string]string)
+		}
+		into[name] = value
+		i = end - 1
+	}
+	return into
+}
+
+func isAnnotationNameByte(c byte, first bool) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		return true
+	case c >= '0' && c <= '9', c == '_', c == '-':
+		return !first
+	}
+	return false
+}
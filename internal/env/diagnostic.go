@@ -0,0 +1,26 @@
+package env
+
+import "fmt"
+
+// DiagnosticKind classifies a syntax problem found while parsing.
+type DiagnosticKind string
+
+const (
+	DiagMalformedLine     DiagnosticKind = "malformed-line"
+	DiagUnterminatedQuote DiagnosticKind = "unterminated-quote"
+	DiagInvalidKey        DiagnosticKind = "invalid-key"
+	DiagTrailingGarbage   DiagnosticKind = "trailing-garbage"
+)
+
+// Diagnostic describes a syntax problem at a 1-based line and column.
+type Diagnostic struct {
+	Kind    DiagnosticKind
+	Key     string // empty when the line has no usable key
+	Line    int
+	Column  int
+	Message string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%d:%d: %s", d.Line, d.Column, d.Message)
+}
@@ -0,0 +1,215 @@
+package env
+
+import "strings"
+
+// Dialect describes how a dotenv loader reads quotes, escapes and comments,
+// so the linted values match what the application will see.
+type Dialect struct {
+	Name string
+
+	// Quotes lists the characters that open a quoted value.
+	Quotes string
+	// Multiline lists the quote characters whose values may span lines.
+	Multiline string
+	// EscapedQuotes means a backslash-escaped quote does not close the value.
+	EscapedQuotes bool
+
+	// DoubleEscapes and SingleEscapes map the character after a backslash
+	// to its replacement inside double and single quotes. A nil map leaves
+	// backslashes untouched.
+	DoubleEscapes map[byte]string
+	SingleEscapes map[byte]string
+	// KeepUnknownEscapes keeps the backslash of escapes not in the map.
+	KeepUnknownEscapes bool
+
+	// InlineCommentSpace requires whitespace before "#" in unquoted values.
+	InlineCommentSpace bool
+	// Expand means the loader interpolates $VAR references.
+	Expand bool
+	// LiteralSingle means single-quoted values are never interpolated.
+	LiteralSingle bool
+}
+
+// Default is the dialect envlint has always used: no escape processing,
+// and only double-quoted values may span lines.
+var Default = Dialect{
+	Name:               "default",
+	Quotes:             `"'`,
+	Multiline:          `"`,
+	InlineCommentSpace: true,
+	Expand:             true,
+}
+
+var dialects = []Dialect{
+	Default,
+	{
+		// github.com/joho/godotenv
+		Name:          "godotenv",
+		Quotes:        `"'`,
+		Multiline:     `"'`,
+		EscapedQuotes: true,
+		DoubleEscapes: map[byte]string{
+			'n': "\n", 'r': "\r", '$': `\$`,
+		},
+		InlineCommentSpace: true,
+		Expand:             true,
+		LiteralSingle:      true,
+	},
+	{
+		// python-dotenv
+		Name:          "python-dotenv",
+		Quotes:        `"'`,
+		Multiline:     `"'`,
+		EscapedQuotes: true,
+		DoubleEscapes: map[byte]string{
+			'\\': `\`, '\'': `'`, '"': `"`, 'a': "\a", 'b': "\b",
+			'f': "\f", 'n': "\n", 'r': "\r", 't': "\t", 'v': "\v",
+		},
+		SingleEscapes: map[byte]string{
+			'\\': `\`, '\'': `'`,
+		},
+		KeepUnknownEscapes: true,
+		InlineCommentSpace: true,
+		Expand:             true,
+		LiteralSingle:      true,
+	},
+	{
+		// Node's dotenv package; expansion needs dotenv-expand.
+		Name:          "node",
+		Quotes:        "\"'`",
+		Multiline:     "\"'`",
+		EscapedQuotes: true,
+		DoubleEscapes: map[byte]string{
+			'n': "\n", 'r': "\r",
+		},
+		KeepUnknownEscapes: true,
+	},
+	{
+		// docker compose env_file and .env
+		Name:          "compose",
+		Quotes:        `"'`,
+		Multiline:     `"'`,
+		EscapedQuotes: true,
+		DoubleEscapes: map[byte]string{
+			'n': "\n", 'r': "\r", 't': "\t", '\\': `\`, '"': `"`, '$': `\$`,
+		},
+		KeepUnknownEscapes: true,
+		InlineCommentSpace: true,
+		Expand:             true,
+		LiteralSingle:      true,
+	},
+}
+
+// LookupDialect returns the dialect with the given name. An empty name
+// selects Default.
+func LookupDialect(name string) (Dialect, bool) {
+	if name == "" {
+		return Default, true
+	}
+	for _, d := range dialects {
+		if d.Name == name {
+			return d, true
+		}
+	}
+	return Dialect{}, false
+}
+
+// DialectNames returns the names of all supported dialects.
+func DialectNames() []string {
+	names := make([]string, len(dialects))
+	for i, d := range dialects {
+		names[i] = d.Name
+	}
+	return names
+}
+
+func (d Dialect) isQuote(c byte) bool {
+	return strings.IndexByte(d.Quotes, c) >= 0
+}
+
+func (d Dialect) isMultiline(quote byte) bool {
+	return strings.IndexByte(d.Multiline, quote) >= 0
+}
+
+// expands reports whether values in the given quote style are interpolated.
+func (d Dialect) expands(quote byte) bool {
+	if !d.Expand {
+		return false
+	}
+	return quote != '\'' || !d.LiteralSingle
+}
+
+// closingQuote returns the index of the quote that closes a value in s,
+// or -1 when s does not contain one.
+func (d Dialect) closingQuote(s string, quote byte) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] != quote {
+			continue
+		}
+		if d.EscapedQuotes && precedingBackslashes(s, i)%2 == 1 {
+			continue
+		}
+		return i
+	}
+	return -1
+}
+
+// escapes returns the escape table of the quote style, or nil.
+func (d Dialect) escapes(quote byte) map[byte]string {
+	switch quote {
+	case '"':
+		return d.DoubleEscapes
+	case '\'':
+		return d.SingleEscapes
+	}
+	return nil
+}
+
+// unescape applies the escape sequences of the quote style to s.
+func (d Dialect) unescape(s string, quote byte) string {
+	escapes := d.escapes(quote)
+	if escapes == nil || !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		if rep, ok := escapes[s[i]]; ok {
+			b.WriteString(rep)
+			continue
+		}
+		if d.KeepUnknownEscapes {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// commentStart returns the index of an inline comment in an unquoted value,
+// or -1 when there is none.
+func (d Dialect) commentStart(s string) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] != '#' {
+			continue
+		}
+		if i == 0 || !d.InlineCommentSpace || s[i-1] == ' ' || s[i-1] == '\t' {
+			return i
+		}
+	}
+	return -1
+}
+
+func precedingBackslashes(s string, i int) int {
+	n := 0
+	for i > 0 && s[i-1] == '\\' {
+		n++
+		i--
+	}
+	return n
+}
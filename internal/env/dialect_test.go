@@ -0,0 +1,84 @@
+package env
+
+import "testing"
+
+func TestParseDialects(t *testing.T) {
+	content := `ESCAPED="a \"b\" c"
+NEWLINE="one\ntwo"
+SINGLE='lit\n $HOME'
+HASH=foo#bar
+`
+	tests := []struct {
+		dialect string
+		key     string
+		want    string
+	}{
+		{"default", "ESCAPED", `a \`},
+		{"godotenv", "ESCAPED", `a "b" c`},
+		{"python-dotenv", "ESCAPED", `a "b" c`},
+		{"node", "ESCAPED", `a \"b\" c`},
+		{"compose", "ESCAPED", `a "b" c`},
+
+		{"default", "NEWLINE", `one\ntwo`},
+		{"godotenv", "NEWLINE", "one\ntwo"},
+		{"node", "NEWLINE", "one\ntwo"},
+
+		{"python-dotenv", "SINGLE", `lit\n $HOME`},
+		{"godotenv", "SINGLE", `lit\n $HOME`},
+
+		{"default", "HASH", "foo#bar"},
+		{"node", "HASH", "foo"},
+	}
+
+	for _, tt := range tests {
+		d, ok := LookupDialect(tt.dialect)
+		if !ok {
+			t.Fatalf("unknown dialect %q", tt.dialect)
+		}
+		m := ParseEntries(ParseStringDialect(content, d).Entries)
+		if got := m[tt.key].Value; got != tt.want {
+			t.Errorf("%s %s: expected %q, got %q", tt.dialect, tt.key, tt.want, got)
+		}
+	}
+}
+
+func TestDialectSingleQuotesAreLiteral(t *testing.T) {
+	content := "A='${HOME}'\nB=\"${HOME}\"\n"
+
+	d, _ := LookupDialect("godotenv")
+	entries := ParseStringDialect(content, d).Entries
+	if entries[0].IsRef {
+		t.Error("single-quoted value should not be a ref in godotenv")
+	}
+	if !entries[1].IsRef {
+		t.Error("double-quoted value should be a ref in godotenv")
+	}
+
+	d, _ = LookupDialect("node")
+	entries = ParseStringDialect(content, d).Entries
+	if entries[1].IsRef {
+		t.Error("node dotenv does not expand references")
+	}
+}
+
+func TestDialectMultilineSingleQuotes(t *testing.T) {
+	content := "KEY='line one\nline two'\nNEXT=1\n"
+
+	d, _ := LookupDialect("python-dotenv")
+	f := ParseStringDialect(content, d)
+	if len(f.Diagnostics) != 0 {
+		t.Fatalf("expected no diagnostics, got %v", f.Diagnostics)
+	}
+	if len(f.Entries) != 2 || f.Entries[0].Value != "line one\nline two" {
+		t.Errorf("unexpected entries: %+v", f.Entries)
+	}
+}
+
+func TestLookupDialectUnknown(t *testing.T) {
+	if _, ok := LookupDialect("bash"); ok {
+		t.Error("expected unknown dialect")
+	}
+	if d, ok := LookupDialect(""); !ok || d.Name != "default" {
+		t.Error("expected empty name to select the default dialect")
+	}
+}
@@ -0,0 +1,52 @@
+package env
+
+import "strings"
+
+// DirectiveKind identifies an envlint comment directive.
+type DirectiveKind string
+
+const (
+	DisableLine     DirectiveKind = "envlint-disable-line"
+	DisableNextLine DirectiveKind = "envlint-disable-next-line"
+	Disable         DirectiveKind = "envlint-disable"
+	Enable          DirectiveKind = "envlint-enable"
+)
+
+// Directive is an envlint comment directive that suppresses rules. Rules
+// is empty when the directive applies to every rule.
+type Directive struct {
+	Kind  DirectiveKind
+	Rules []string
+	Line  int
+}
+
+// parseDirective reads a directive from comment text, which must start
+// with it, e.g. "envlint-disable-line invalid-url, invalid-port".
+func parseDirective(comment string, line int) (Directive, bool) {
+	fields := strings.FieldsFunc(comment, func(r rune) bool {
+		return r == ' ' || r == '\t' || r == ','
+	})
+	if len(fields) == 0 {
+		return Directive{}, false
+	}
+	switch kind := DirectiveKind(fields[0]); kind {
+	case DisableLine, DisableNextLine, Disable, Enable:
+		// Anything after "--" explains the directive
+		rules := fields[1:]
+		for i, f := range rules {
+			if strings.HasPrefix(f, "--") {
+				rules = rules[:i]
+				break
+			}
+		}
+		return Directive{Kind: kind, Rules: rules, Line: line}, true
+	}
+	return Directive{}, false
+}
+
+// IsDirective reports whether comment text, without its "#", is an
+// envlint directive.
+func IsDirective(comment string) bool {
+	_, ok := parseDirective(strings.TrimSpace(comment), 0)
+	return ok
+}
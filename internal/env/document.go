@@ -0,0 +1,480 @@
+package env
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+)
+
+// NodeKind identifies what a Document node holds.
+type NodeKind int
+
+const (
+	BlankNode NodeKind = iota
+	CommentNode
+	EntryNode
+	InvalidNode // a line the parser could not read as an entry
+)
+
+// Node is one logical line of an env file. Raw holds its exact source text
+// including line terminators; a multiline value spans several lines.
+type Node struct {
+	Kind  NodeKind
+	Raw   string
+	Entry Entry // set for EntryNode
+
+	// valueStart and valueEnd locate the value token, quotes included,
+	// within Raw.
+	valueStart, valueEnd int
+}
+
+// Document is a lossless syntax tree of an env file. String returns the
+// original content byte for byte until the document is edited; edits only
+// rewrite the nodes they touch.
+type Document struct {
+	Nodes       []Node
+	Diagnostics []Diagnostic // found when the document was parsed
+	Directives  []Directive  // envlint comment directives, as parsed
+
+	dialect Dialect
+	newline string
+}
+
+// ReadDocument reads and parses an env file into a Document.
+func ReadDocument(path string, d Dialect) (*Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open %s: %w", path, err)
+	}
+	return ParseDocument(string(data), d), nil
+}
+
+// String returns the document's content.
+func (doc *Document) String() string {
+	var b strings.Builder
+	for _, n := range doc.Nodes {
+		b.WriteString(n.Raw)
+	}
+	return b.String()
+}
+
+// Entries returns the document's entries in order, with line numbers
+// reflecting any edits.
+func (doc *Document) Entries() []Entry {
+	var entries []Entry
+	line := 1
+	for _, n := range doc.Nodes {
+		if n.Kind == EntryNode {
+			e := n.Entry
+			e.LineNum = line
+			entries = append(entries, e)
+		}
+		line += strings.Count(n.Raw, "\n")
+	}
+	return entries
+}
+
+// Has reports whether key is defined in the document.
+func (doc *Document) Has(key string) bool {
+	return doc.index(key) >= 0
+}
+
+// Set changes the value of every definition of key, keeping its export
+// prefix, quote style and inline comment. A key that is not defined yet is
+// appended.
+func (doc *Document) Set(key, value string) {
+	found := false
+	for i := range doc.Nodes {
+		n := &doc.Nodes[i]
+		if n.Kind != EntryNode || n.Entry.Key != key {
+			continue
+		}
+		found = true
+
+		token := doc.dialect.formatValue(value, n.Entry.Quote)
+		after := n.Raw[n.valueEnd:]
+		if token != "" && after != "" && after[0] == '#' {
+			token += " "
+		}
+		n.Raw = n.Raw[:n.valueStart] + token + after
+		n.valueEnd = n.valueStart + len(token)
+		n.Entry.Value = value
+		n.Entry.Quote = quoteOf(token)
+		n.Entry.IsRef = doc.dialect.expands(n.Entry.Quote) && refPattern.MatchString(value)
+	}
+	if !found {
+		doc.Append(Entry{Key: key, Value: value})
+	}
+}
+
+// Annotate appends text to the inline comment of every definition of key,
+// adding a comment where there is none, and reports how many were changed.
+func (doc *Document) Annotate(key, text string) int {
+	changed := 0
+	for i := range doc.Nodes {
+		n := &doc.Nodes[i]
+		if n.Kind != EntryNode || n.Entry.Key != key {
+			continue
+		}
+		after := n.Raw[n.valueEnd:]
+		body := strings.TrimRight(after, "\r\n")
+		newline := after[len(body):]
+		if body = strings.TrimRight(body, " \t"); body == "" {
+			body = " #"
+		}
+		n.Raw = n.Raw[:n.valueEnd] + body + " " + text + newline
+
+		if n.Entry.Comment != "" {
+			n.Entry.Comment += " "
+		}
+		n.Entry.Comment += text
+		n.Entry.Annotations = parseAnnotations(text, maps.Clone(n.Entry.Annotations))
+		changed++
+	}
+	return changed
+}
+
+// StripDirectives removes envlint directives from the document: comment
+// lines that hold one and inline comments of entries. It reports how many
+// were removed.
+func (doc *Document) StripDirectives() int {
+	removed := 0
+	nodes := doc.Nodes[:0]
+	for _, n := range doc.Nodes {
+		switch {
+		case n.Kind == CommentNode && IsDirective(strings.TrimSpace(n.Raw)[1:]):
+			removed++
+			continue
+		case n.Kind == EntryNode && IsDirective(n.Entry.Comment):
+			after := n.Raw[n.valueEnd:]
+			n.Raw = n.Raw[:n.valueEnd] + after[len(strings.TrimRight(after, "\r\n")):]
+			n.Entry.Comment = ""
+			removed++
+		}
+		nodes = append(nodes, n)
+	}
+	doc.Nodes = nodes
+	doc.Directives = nil
+	return removed
+}
+
+// Append adds e as a new line at the end of the document.
+func (doc *Document) Append(e Entry) {
+	doc.insert(len(doc.Nodes), e)
+}
+
+// InsertAfter adds e as a new line directly after the last definition of
+// the key after.
+func (doc *Document) InsertAfter(after string, e Entry) error {
+	i := doc.index(after)
+	if i < 0 {
+		return fmt.Errorf("key %s not found", after)
+	}
+	doc.insert(i+1, e)
+	return nil
+}
+
+// InsertGrouped adds e after the last key that shares the most leading
+// name segments with it, so DB_REPLICA_HOST lands next to the DB_ keys.
+// Without such a key, e is appended.
+func (doc *Document) InsertGrouped(e Entry) {
+	segments := strings.Split(e.Key, "_")
+	best, at := 0, -1
+	for i, n := range doc.Nodes {
+		if n.Kind != EntryNode {
+			continue
+		}
+		other := strings.Split(n.Entry.Key, "_")
+		shared := 0
+		for shared < len(segments)-1 && shared < len(other)-1 && segments[shared] == other[shared] {
+			shared++
+		}
+		if shared > 0 && shared >= best {
+			best, at = shared, i
+		}
+	}
+	if at < 0 {
+		doc.Append(e)
+		return
+	}
+	doc.insert(at+1, e)
+}
+
+// Delete removes every definition of key and reports how many were removed.
+// Comments above the key are left in place.
+func (doc *Document) Delete(key string) int {
+	removed := 0
+	nodes := doc.Nodes[:0]
+	for _, n := range doc.Nodes {
+		if n.Kind == EntryNode && n.Entry.Key == key {
+			removed++
+			continue
+		}
+		nodes = append(nodes, n)
+	}
+	doc.Nodes = nodes
+	return removed
+}
+
+// DeleteWithComments removes every definition of key together with the
+// comment lines directly above it, such as its description and
+// annotations. Comments separated from the key by a blank line stay. It
+// reports how many definitions were removed.
+func (doc *Document) DeleteWithComments(key string) int {
+	removed := 0
+	nodes := doc.Nodes[:0]
+	for _, n := range doc.Nodes {
+		if n.Kind == EntryNode && n.Entry.Key == key {
+			for len(nodes) > 0 && nodes[len(nodes)-1].Kind == CommentNode {
+				nodes = nodes[:len(nodes)-1]
+			}
+			removed++
+			continue
+		}
+		nodes = append(nodes, n)
+	}
+	doc.Nodes = nodes
+	return removed
+}
+
+// Dedupe removes every definition of key but the one the loader uses: the
+// first when keepFirst is set, otherwise the last. It reports how many were
+// removed.
+func (doc *Document) Dedupe(key string, keepFirst bool) int {
+	keep := doc.index(key)
+	if keepFirst {
+		keep = slices.IndexFunc(doc.Nodes, func(n Node) bool {
+			return n.Kind == EntryNode && n.Entry.Key == key
+		})
+	}
+	if keep < 0 {
+		return 0
+	}
+
+	removed := 0
+	nodes := doc.Nodes[:0]
+	for i, n := range doc.Nodes {
+		if i != keep && n.Kind == EntryNode && n.Entry.Key == key {
+			removed++
+			continue
+		}
+		nodes = append(nodes, n)
+	}
+	doc.Nodes = nodes
+	return removed
+}
+
+// CommentOut turns every definition of key into a comment holding its
+// original text and reports how many were changed.
+func (doc *Document) CommentOut(key string) int {
+	changed := 0
+	for i := range doc.Nodes {
+		n := &doc.Nodes[i]
+		if n.Kind != EntryNode || n.Entry.Key != key {
+			continue
+		}
+		var b strings.Builder
+		for _, line := range strings.SplitAfter(n.Raw, "\n") {
+			if line != "" {
+				b.WriteString("# ")
+				b.WriteString(line)
+			}
+		}
+		*n = Node{Kind: CommentNode, Raw: b.String()}
+		changed++
+	}
+	return changed
+}
+
+// SetExport adds or removes the "export " prefix on every definition of
+// key and reports how many were changed.
+func (doc *Document) SetExport(key string, export bool) int {
+	changed := 0
+	for i := range doc.Nodes {
+		n := &doc.Nodes[i]
+		if n.Kind != EntryNode || n.Entry.Key != key || n.Entry.Export == export {
+			continue
+		}
+		indent := len(n.Raw) - len(strings.TrimLeft(n.Raw, " \t"))
+		delta := len("export ")
+		if export {
+			n.Raw = n.Raw[:indent] + "export " + n.Raw[indent:]
+		} else {
+			rest := strings.TrimLeft(n.Raw[indent+len("export"):], " \t")
+			delta = len(rest) - len(n.Raw[indent:])
+			n.Raw = n.Raw[:indent] + rest
+		}
+		n.valueStart += delta
+		n.valueEnd += delta
+		n.Entry.Export = export
+		changed++
+	}
+	return changed
+}
+
+// Move places the last definition of key directly after the last
+// definition of after. An empty after moves the key to the top.
+func (doc *Document) Move(key, after string) error {
+	from := doc.index(key)
+	if from < 0 {
+		return fmt.Errorf("key %s not found", key)
+	}
+	n := doc.Nodes[from]
+	doc.Nodes = append(doc.Nodes[:from], doc.Nodes[from+1:]...)
+
+	to := 0
+	if after != "" {
+		i := doc.index(after)
+		if i < 0 {
+			// Put the node back before failing
+			doc.Nodes = append(doc.Nodes[:from], append([]Node{n}, doc.Nodes[from:]...)...)
+			return fmt.Errorf("key %s not found", after)
+		}
+		to = i + 1
+	}
+	doc.ensureNewline(to - 1)
+	if !strings.HasSuffix(n.Raw, "\n") {
+		n.Raw += doc.newline
+	}
+	doc.Nodes = append(doc.Nodes[:to], append([]Node{n}, doc.Nodes[to:]...)...)
+	return nil
+}
+
+// index returns the node index of the last definition of key, or -1.
+func (doc *Document) index(key string) int {
+	for i := len(doc.Nodes) - 1; i >= 0; i-- {
+		if doc.Nodes[i].Kind == EntryNode && doc.Nodes[i].Entry.Key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// insert renders e and places it at node index i.
+func (doc *Document) insert(i int, e Entry) {
+	doc.ensureNewline(i - 1)
+	n := doc.render(e)
+	doc.Nodes = append(doc.Nodes[:i], append([]Node{n}, doc.Nodes[i:]...)...)
+}
+
+// ensureNewline terminates node i if it is the last line of a file that
+// does not end in a newline.
+func (doc *Document) ensureNewline(i int) {
+	if i >= 0 && i < len(doc.Nodes) && !strings.HasSuffix(doc.Nodes[i].Raw, "\n") {
+		doc.Nodes[i].Raw += doc.newline
+	}
+}
+
+// render formats e as a new entry line.
+func (doc *Document) render(e Entry) Node {
+	var b strings.Builder
+	if e.Export {
+		b.WriteString("export ")
+	}
+	b.WriteString(e.Key)
+	b.WriteString("=")
+	start := b.Len()
+	token := doc.dialect.formatValue(e.Value, e.Quote)
+	b.WriteString(token)
+	if e.Comment != "" {
+		b.WriteString(" # ")
+		b.WriteString(e.Comment)
+	}
+	b.WriteString(doc.newline)
+
+	e.Quote = quoteOf(token)
+	e.IsRef = doc.dialect.expands(e.Quote) && refPattern.MatchString(e.Value)
+	return Node{
+		Kind:       EntryNode,
+		Raw:        b.String(),
+		Entry:      e,
+		valueStart: start,
+		valueEnd:   start + len(token),
+	}
+}
+
+// formatValue renders value as a token the dialect reads back unchanged,
+// using quote when possible.
+func (d Dialect) formatValue(value string, quote byte) string {
+	if value == "" {
+		return ""
+	}
+	if quote == 0 && !needsQuotes(value) {
+		return value
+	}
+	for _, q := range []byte{quote, '"', '\''} {
+		if q == 0 {
+			continue
+		}
+		if token, ok := d.quoteWith(value, q); ok {
+			return token
+		}
+	}
+	return value
+}
+
+// quoteWith wraps value in quote q, escaping as needed. It reports false
+// when the dialect cannot represent value in that quote style.
+func (d Dialect) quoteWith(value string, q byte) (string, bool) {
+	if !d.isQuote(q) {
+		return "", false
+	}
+	escapes := d.escapes(q)
+	canEscape := func(c byte) bool {
+		rep, ok := escapes[c]
+		return (ok && rep == string(c)) || (escapes != nil && !ok && !d.KeepUnknownEscapes)
+	}
+
+	var b strings.Builder
+	b.WriteByte(q)
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c == q:
+			if !d.EscapedQuotes || !canEscape(c) {
+				return "", false
+			}
+			b.WriteByte('\\')
+		case c == '\\' && escapes != nil:
+			if !canEscape(c) {
+				return "", false
+			}
+			b.WriteByte('\\')
+		case c == '\n' && !d.isMultiline(q):
+			if escapes['n'] != "\n" {
+				return "", false
+			}
+			b.WriteString(`\n`)
+			continue
+		}
+		b.WriteByte(c)
+	}
+	b.WriteByte(q)
+	return b.String(), true
+}
+
+// needsQuotes reports whether value cannot be written unquoted.
+func needsQuotes(value string) bool {
+	return value != strings.TrimSpace(value) || strings.ContainsAny(value, "#\"'`\n")
+}
+
+func quoteOf(token string) byte {
+	if token == "" {
+		return 0
+	}
+	switch token[0] {
+	case '"', '\'', '`':
+		return token[0]
+	}
+	return 0
+}
+
+// detectNewline returns the line terminator used by content.
+func detectNewline(content string) string {
+	if i := strings.IndexByte(content, '\n'); i > 0 && content[i-1] == '\r' {
+		return "\r\n"
+	}
+	return "\n"
+}
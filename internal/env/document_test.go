@@ -0,0 +1,196 @@
+package env
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDocumentRoundTrip(t *testing.T) {
+	inputs := []string{
+		"",
+		"FOO=bar\n",
+		"FOO=bar",
+		"# header\n\nexport A=1 # note\n  B = \"two\"  \n",
+		"CRLF=1\r\nNEXT='x'\r\n",
+		"MULTI=\"line one\nline two\" # trailing\nAFTER=done\n",
+		"garbage line\nKEY=\"unterminated\nrest\n",
+		"\n\n\n",
+	}
+	for _, in := range inputs {
+		doc := ParseDocument(in, Default)
+		if got := doc.String(); got != in {
+			t.Errorf("round trip mismatch:\n in  %q\n out %q", in, got)
+		}
+	}
+}
+
+func TestDocumentNodes(t *testing.T) {
+	doc := ParseDocument("# c\n\nA=1\nbroken\nB=\"x\ny\"\n", Default)
+	var kinds []NodeKind
+	for _, n := range doc.Nodes {
+		kinds = append(kinds, n.Kind)
+	}
+	want := []NodeKind{CommentNode, BlankNode, EntryNode, InvalidNode, EntryNode}
+	if !slices.Equal(kinds, want) {
+		t.Errorf("expected kinds %v, got %v", want, kinds)
+	}
+	entries := doc.Entries()
+	if len(entries) != 2 || entries[1].LineNum != 5 {
+		t.Errorf("unexpected entries: %+v", entries)
+	}
+}
+
+func TestDocumentSet(t *testing.T) {
+	doc := ParseDocument("# db\nexport DB=\"old\" # keep me\nEMPTY= # required\nPLAIN=1", Default)
+
+	doc.Set("DB", "new value")
+	doc.Set("EMPTY", "filled")
+	doc.Set("PLAIN", "has # hash")
+	doc.Set("ADDED", "yes")
+
+	want := "# db\nexport DB=\"new value\" # keep me\nEMPTY=filled # required\nPLAIN=\"has # hash\"\nADDED=yes\n"
+	if got := doc.String(); got != want {
+		t.Errorf("unexpected document:\n got  %q\n want %q", got, want)
+	}
+
+	// The edited document parses back to the same values
+	m := ParseEntries(ParseString(doc.String()).Entries)
+	if m["DB"].Value != "new value" || m["PLAIN"].Value != "has # hash" || m["ADDED"].Value != "yes" {
+		t.Errorf("edited document does not parse back: %+v", m)
+	}
+}
+
+func TestDocumentSetEscapes(t *testing.T) {
+	d, _ := LookupDialect("godotenv")
+	doc := ParseDocument("QUOTE=\"x\"\n", d)
+	doc.Set("QUOTE", `say "hi"`+"\nbye")
+
+	m := ParseEntries(ParseStringDialect(doc.String(), d).Entries)
+	if got := m["QUOTE"].Value; got != "say \"hi\"\nbye" {
+		t.Errorf("expected escaped value to round trip, got %q from %q", got, doc.String())
+	}
+}
+
+func TestDocumentInsertDeleteMove(t *testing.T) {
+	doc := ParseDocument("# app\nA=1\nB=2\n\n# db\nC=3", Default)
+
+	if err := doc.InsertAfter("A", Entry{Key: "A2", Comment: "new"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.InsertAfter("MISSING", Entry{Key: "X"}); err == nil {
+		t.Error("expected error for unknown key")
+	}
+	if n := doc.Delete("B"); n != 1 {
+		t.Errorf("expected 1 deletion, got %d", n)
+	}
+	if err := doc.Move("C", "A"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "# app\nA=1\nC=3\nA2= # new\n\n# db\n"
+	if got := doc.String(); got != want {
+		t.Errorf("unexpected document:\n got  %q\n want %q", got, want)
+	}
+
+	if err := doc.Move("A2", ""); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Entries()[0].Key != "A2" || doc.Entries()[0].LineNum != 1 {
+		t.Errorf("expected A2 on line 1, got %+v", doc.Entries()[0])
+	}
+}
+
+func TestDocumentKeepsCRLF(t *testing.T) {
+	doc := ParseDocument("A=1\r\n", Default)
+	doc.Append(Entry{Key: "B", Value: "2"})
+	if got := doc.String(); got != "A=1\r\nB=2\r\n" {
+		t.Errorf("expected CRLF line endings, got %q", got)
+	}
+}
+
+func TestDocumentDedupeCommentOutExport(t *testing.T) {
+	doc := ParseDocument("A=1\nexport  B=2 # note\nA=2\nC=\"x\ny\"\nA=3\n", Default)
+
+	if n := doc.Dedupe("A", true); n != 2 {
+		t.Errorf("expected 2 duplicates removed, got %d", n)
+	}
+	if n := doc.CommentOut("C"); n != 1 {
+		t.Errorf("expected C to be commented out, got %d", n)
+	}
+	doc.SetExport("B", false)
+	doc.SetExport("A", true)
+	doc.Set("B", "two")
+
+	want := "export A=1\nB=two # note\n# C=\"x\n# y\"\n"
+	if got := doc.String(); got != want {
+		t.Errorf("unexpected document:\n got  %q\n want %q", got, want)
+	}
+	if doc.Has("C") {
+		t.Error("a commented out key is no longer defined")
+	}
+
+	doc = ParseDocument("A=1\nA=2\n", Default)
+	doc.Dedupe("A", false)
+	if got := doc.String(); got != "A=2\n" {
+		t.Errorf("expected the last definition to be kept, got %q", got)
+	}
+}
+
+func TestDocumentInsertGrouped(t *testing.T) {
+	doc := ParseDocument("# app\nAPP_NAME=\n\n# db\nDB_HOST=\nDB_REPLICA_HOST=\nDB_NAME=\n", Default)
+
+	doc.InsertGrouped(Entry{Key: "APP_PORT"})
+	doc.InsertGrouped(Entry{Key: "DB_REPLICA_PORT", Comment: "read only"})
+	doc.InsertGrouped(Entry{Key: "DB_USER"})
+	doc.InsertGrouped(Entry{Key: "SENTRY_DSN"})
+	doc.InsertGrouped(Entry{Key: "APPLE"})
+
+	want := "# app\nAPP_NAME=\nAPP_PORT=\n\n# db\nDB_HOST=\nDB_REPLICA_HOST=\nDB_REPLICA_PORT= # read only\nDB_NAME=\nDB_USER=\nSENTRY_DSN=\nAPPLE=\n"
+	if got := doc.String(); got != want {
+		t.Errorf("unexpected document:\n got  %q\n want %q", got, want)
+	}
+}
+
+func TestDocumentAnnotate(t *testing.T) {
+	doc := ParseDocument("PORT=3000\r\nURL=http://x # public  \r\nNAME=\r\n", Default)
+	doc.Annotate("PORT", "@type=port")
+	doc.Annotate("URL", "@type=url")
+	doc.Annotate("NAME", "@required")
+
+	want := "PORT=3000 # @type=port\r\nURL=http://x # public @type=url\r\nNAME= # @required\r\n"
+	if got := doc.String(); got != want {
+		t.Errorf("unexpected document:\n got  %q\n want %q", got, want)
+	}
+	for _, e := range ParseDocument(doc.String(), Default).Entries() {
+		if len(e.Annotations) != 1 {
+			t.Errorf("%s: expected one annotation, got %v", e.Key, e.Annotations)
+		}
+	}
+	if e := doc.Entries()[1]; e.Comment != "public @type=url" || e.Annotations["type"] != "url" {
+		t.Errorf("unexpected entry: %+v", e)
+	}
+}
+
+func TestDocumentStripDirectives(t *testing.T) {
+	doc := ParseDocument("# envlint-disable-next-line invalid-url\nA=x # envlint-disable-line invalid-port\n# keep me\nB=\"y\" # note\n", Default)
+	if n := doc.StripDirectives(); n != 2 {
+		t.Errorf("expected 2 directives removed, got %d", n)
+	}
+	if want := "A=x\n# keep me\nB=\"y\" # note\n"; doc.String() != want {
+		t.Errorf("unexpected document:\n%q\nwant\n%q", doc.String(), want)
+	}
+	doc.Set("A", "z")
+	if entries := doc.Entries(); entries[0].Value != "z" || entries[0].Comment != "" {
+		t.Errorf("unexpected entry after strip: %+v", entries[0])
+	}
+}
+
+func TestDocumentDeleteWithComments(t *testing.T) {
+	doc := ParseDocument("# Database\n\n# Legacy host\n# @type=url\nOLD_HOST=\nDB_HOST=\n", Default)
+	if n := doc.DeleteWithComments("OLD_HOST"); n != 1 {
+		t.Errorf("expected 1 definition removed, got %d", n)
+	}
+	if want := "# Database\n\nDB_HOST=\n"; doc.String() != want {
+		t.Errorf("unexpected document:\n%q\nwant\n%q", doc.String(), want)
+	}
+}
@@ -8,4 +8,18 @@ type Entry struct {
 	LineNum  int
 	Required bool // determined by "# required" annotation or non-empty example value
 	IsRef    bool // value contains variable reference ($VAR or ${VAR})
+	Export   bool // line starts with "export "
+	Quote    byte // opening quote character, 0 for unquoted values
+
+	// Annotations holds @name=value schema annotations from the inline
+	// comment and the comment lines directly above the entry.
+	Annotations map[string]string
+}
+
+// File is a parsed env file.
+type File struct {
+	Path        string
+	Entries     []Entry
+	Diagnostics []Diagnostic
+	Directives  []Directive
 }
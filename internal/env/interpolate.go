@@ -0,0 +1,294 @@
+package env
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// RefProblemKind classifies a reference that could not be expanded.
+type RefProblemKind string
+
+const (
+	RefUndefined RefProblemKind = "undefined"
+	RefCircular  RefProblemKind = "circular"
+	RefRequired  RefProblemKind = "required" // ${VAR:?message} with VAR unset
+)
+
+// RefProblem describes a reference that could not be expanded.
+type RefProblem struct {
+	Kind RefProblemKind
+	Key  string   // key whose value holds the reference
+	Name string   // referenced variable
+	Path []string // reference chain of a circular reference, e.g. A, B, A
+
+	// Message is the text of a ${VAR:?message} reference.
+	Message string
+}
+
+// Resolution holds the expanded value of every key.
+type Resolution struct {
+	Values     map[string]string
+	Problems   []RefProblem
+	unresolved map[string]bool
+}
+
+// Unresolved reports whether the value of key depends on a reference that
+// could not be expanded, so its expanded value is not what the app sees.
+func (r Resolution) Unresolved(key string) bool {
+	return r.unresolved[key]
+}
+
+// Resolve expands $VAR and ${VAR} references in the values of entries,
+// including the POSIX forms ${VAR:-default}, ${VAR-default},
+// ${VAR:?message}, ${VAR?message}, ${VAR:+alt} and ${VAR+alt}.
+// References are looked up among the entries first and then through lookup,
+// which may be nil. A key referencing itself, as in PATH=$PATH:/bin, refers
+// to the process environment.
+func Resolve(entries map[string]Entry, lookup func(string) (string, bool)) Resolution {
+	r := &resolver{
+		entries:    entries,
+		lookup:     lookup,
+		state:      make(map[string]int),
+		values:     make(map[string]string, len(entries)),
+		unresolved: make(map[string]bool),
+		cycles:     make(map[string]bool),
+	}
+
+	keys := make([]string, 0, len(entries))
+	for k := range entries {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		r.resolve(k)
+	}
+
+	return Resolution{Values: r.values, Problems: r.problems, unresolved: r.unresolved}
+}
+
+const (
+	unvisited = iota
+	visiting
+	resolved
+)
+
+type resolver struct {
+	entries    map[string]Entry
+	lookup     func(string) (string, bool)
+	state      map[string]int
+	stack      []string
+	values     map[string]string
+	unresolved map[string]bool
+	problems   []RefProblem
+	cycles     map[string]bool
+}
+
+func (r *resolver) resolve(key string) string {
+	switch r.state[key] {
+	case resolved:
+		return r.values[key]
+	case visiting:
+		r.cycle(key)
+		return ""
+	}
+
+	e := r.entries[key]
+	if !e.IsRef {
+		r.state[key] = resolved
+		r.values[key] = e.Value
+		return e.Value
+	}
+
+	r.state[key] = visiting
+	r.stack = append(r.stack, key)
+	value := r.expand(key, e.Value)
+	r.stack = r.stack[:len(r.stack)-1]
+	r.state[key] = resolved
+	r.values[key] = value
+	return value
+}
+
+func (r *resolver) expand(key, s string) string {
+	return expand(s, func(ref reference) string {
+		return r.substitute(key, ref)
+	})
+}
+
+// substitute returns the text a reference inside the value of key expands to.
+func (r *resolver) substitute(key string, ref reference) string {
+	v, set := r.lookupVar(key, ref.name)
+	switch ref.op {
+	case ":-":
+		if !set || v == "" {
+			return r.expand(key, ref.arg)
+		}
+	case "-":
+		if !set {
+			return r.expand(key, ref.arg)
+		}
+	case ":+":
+		if set && v != "" {
+			return r.expand(key, ref.arg)
+		}
+		return ""
+	case "+":
+		if set {
+			return r.expand(key, ref.arg)
+		}
+		return ""
+	case ":?", "?":
+		if !set || (ref.op == ":?" && v == "") {
+			r.unresolved[key] = true
+			r.problems = append(r.problems, RefProblem{Kind: RefRequired, Key: key, Name: ref.name, Message: ref.arg})
+			return ""
+		}
+	default:
+		if !set {
+			r.unresolved[key] = true
+			r.problems = append(r.problems, RefProblem{Kind: RefUndefined, Key: key, Name: ref.name})
+			return ""
+		}
+	}
+	return v
+}
+
+// lookupVar returns the value of variable name as seen from the value of key.
+func (r *resolver) lookupVar(key, name string) (string, bool) {
+	if _, ok := r.entries[name]; ok && name != key {
+		v := r.resolve(name)
+		if r.unresolved[name] {
+			r.unresolved[key] = true
+		}
+		return v, true
+	}
+	if r.lookup != nil {
+		return r.lookup(name)
+	}
+	return "", false
+}
+
+// cycle records the reference cycle that leads back to key.
+func (r *resolver) cycle(key string) {
+	start := slices.Index(r.stack, key)
+	path := append(slices.Clone(r.stack[start:]), key)
+	for _, k := range path {
+		r.unresolved[k] = true
+	}
+
+	members := slices.Clone(path[:len(path)-1])
+	sort.Strings(members)
+	id := strings.Join(members, ",")
+	if r.cycles[id] {
+		return
+	}
+	r.cycles[id] = true
+	r.problems = append(r.problems, RefProblem{
+		Kind: RefCircular,
+		Key:  path[0],
+		Name: path[1],
+		Path: path,
+	})
+}
+
+// reference is a parsed $VAR or ${VAR<op><arg>} reference.
+type reference struct {
+	name string
+	op   string // "", ":-", "-", ":?", "?", ":+" or "+"
+	arg  string
+}
+
+var refOps = []string{":-", ":?", ":+", "-", "?", "+"}
+
+// expand replaces every reference in s with the result of fn. An escaped
+// \$ stands for a literal dollar sign.
+func expand(s string, fn func(ref reference) string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '\\' && i+1 < len(s) && s[i+1] == '$' {
+			b.WriteByte('$')
+			i++
+			continue
+		}
+		if c != '$' {
+			b.WriteByte(c)
+			continue
+		}
+		ref, n := scanRef(s[i:])
+		if n == 0 {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteString(fn(ref))
+		i += n - 1
+	}
+	return b.String()
+}
+
+// scanRef reads a reference at the start of s and returns it with the
+// number of bytes consumed, or 0 if s does not start with a reference.
+func scanRef(s string) (reference, int) {
+	if len(s) < 2 {
+		return reference{}, 0
+	}
+	if s[1] != '{' {
+		n := 1
+		for n < len(s) && isNameByte(s[n], n == 1) {
+			n++
+		}
+		if n == 1 {
+			return reference{}, 0
+		}
+		return reference{name: s[1:n]}, n
+	}
+
+	// Find the matching brace; defaults may nest references
+	depth, end := 0, -1
+	for i := 1; i < len(s) && end < 0; i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				end = i
+			}
+		}
+	}
+	if end < 0 {
+		return reference{}, 0
+	}
+
+	body := s[2:end]
+	n := 0
+	for n < len(body) && isNameByte(body[n], n == 0) {
+		n++
+	}
+	if n == 0 {
+		return reference{}, 0
+	}
+	ref := reference{name: body[:n]}
+	if rest := body[n:]; rest != "" {
+		for _, op := range refOps {
+			if arg, ok := strings.CutPrefix(rest, op); ok {
+				ref.op, ref.arg = op, arg
+				break
+			}
+		}
+		if ref.op == "" {
+			return reference{}, 0
+		}
+	}
+	return ref, end + 1
+}
+
+func isNameByte(c byte, first bool) bool {
+	switch {
+	case c == '_', c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z':
+		return true
+	case c >= '0' && c <= '9':
+		return !first
+	}
+	return false
+}
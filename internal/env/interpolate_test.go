@@ -0,0 +1,153 @@
+package env
+
+import (
+	"slices"
+	"testing"
+)
+
+func resolveString(t *testing.T, content string, lookup func(string) (string, bool)) Resolution {
+	t.Helper()
+	return Resolve(ParseEntries(ParseString(content).Entries), lookup)
+}
+
+func TestResolveReferences(t *testing.T) {
+	res := resolveString(t, `BASE_URL=http://localhost
+API_URL=${BASE_URL}/api
+V2_URL=$API_URL/v2
+PRICE=\$HOME
+LITERAL=plain
+`, nil)
+
+	want := map[string]string{
+		"BASE_URL": "http://localhost",
+		"API_URL":  "http://localhost/api",
+		"V2_URL":   "http://localhost/api/v2",
+		"PRICE":    "$HOME",
+		"LITERAL":  "plain",
+	}
+	for k, v := range want {
+		if res.Values[k] != v {
+			t.Errorf("%s: expected %q, got %q", k, v, res.Values[k])
+		}
+	}
+	if len(res.Problems) != 0 {
+		t.Errorf("expected no problems, got %+v", res.Problems)
+	}
+}
+
+func TestResolveUndefined(t *testing.T) {
+	res := resolveString(t, "URL=${HOST}/api\nNEXT=$URL\n", nil)
+
+	if len(res.Problems) != 1 {
+		t.Fatalf("expected 1 problem, got %+v", res.Problems)
+	}
+	p := res.Problems[0]
+	if p.Kind != RefUndefined || p.Key != "URL" || p.Name != "HOST" {
+		t.Errorf("unexpected problem: %+v", p)
+	}
+	if !res.Unresolved("URL") || !res.Unresolved("NEXT") {
+		t.Error("expected URL and NEXT to be unresolved")
+	}
+}
+
+func TestResolveLookupFallback(t *testing.T) {
+	lookup := func(name string) (string, bool) {
+		if name == "HOST" || name == "PATH" {
+			return "value-of-" + name, true
+		}
+		return "", false
+	}
+	res := resolveString(t, "URL=http://${HOST}\nPATH=$PATH:/opt/bin\n", lookup)
+
+	if res.Values["URL"] != "http://value-of-HOST" {
+		t.Errorf("unexpected URL: %q", res.Values["URL"])
+	}
+	if res.Values["PATH"] != "value-of-PATH:/opt/bin" {
+		t.Errorf("expected self reference to use lookup, got %q", res.Values["PATH"])
+	}
+	if len(res.Problems) != 0 {
+		t.Errorf("expected no problems, got %+v", res.Problems)
+	}
+}
+
+func TestResolveCircular(t *testing.T) {
+	res := resolveString(t, "A=${B}\nB=${C}\nC=${A}\nD=${A}\n", nil)
+
+	var cycles []RefProblem
+	for _, p := range res.Problems {
+		if p.Kind == RefCircular {
+			cycles = append(cycles, p)
+		}
+	}
+	if len(cycles) != 1 {
+		t.Fatalf("expected 1 cycle, got %+v", res.Problems)
+	}
+	if want := []string{"A", "B", "C", "A"}; !slices.Equal(cycles[0].Path, want) {
+		t.Errorf("expected path %v, got %v", want, cycles[0].Path)
+	}
+	for _, k := range []string{"A", "B", "C", "D"} {
+		if !res.Unresolved(k) {
+			t.Errorf("expected %s to be unresolved", k)
+		}
+	}
+}
+
+func TestResolveDefaultForms(t *testing.T) {
+	lookup := func(name string) (string, bool) {
+		if name == "FROM_ENV" {
+			return "env", true
+		}
+		return "", false
+	}
+	res := resolveString(t, `EMPTY=
+SET=value
+A=${UNSET:-fallback}
+B=${EMPTY:-fallback}
+C=${EMPTY-fallback}
+D=${SET:+alt}
+E=${UNSET:+alt}
+F=${EMPTY+alt}
+G=${UNSET:-${SET}/nested}
+H=${FROM_ENV:-x}
+`, lookup)
+
+	want := map[string]string{
+		"A": "fallback",
+		"B": "fallback",
+		"C": "",
+		"D": "alt",
+		"E": "",
+		"F": "alt",
+		"G": "value/nested",
+		"H": "env",
+	}
+	for k, v := range want {
+		if res.Values[k] != v {
+			t.Errorf("%s: expected %q, got %q", k, v, res.Values[k])
+		}
+		if res.Unresolved(k) {
+			t.Errorf("%s: expected to be resolved", k)
+		}
+	}
+	if len(res.Problems) != 0 {
+		t.Errorf("expected no problems, got %+v", res.Problems)
+	}
+}
+
+func TestResolveErrorForm(t *testing.T) {
+	res := resolveString(t, "EMPTY=\nA=${TOKEN:?token must be set}\nB=${EMPTY:?}\nC=${EMPTY?set but empty is fine}\n", nil)
+
+	if len(res.Problems) != 2 {
+		t.Fatalf("expected 2 problems, got %+v", res.Problems)
+	}
+	p := res.Problems[0]
+	if p.Kind != RefRequired || p.Key != "A" || p.Name != "TOKEN" || p.Message != "token must be set" {
+		t.Errorf("unexpected problem: %+v", p)
+	}
+	if res.Problems[1].Key != "B" || res.Problems[1].Message != "" {
+		t.Errorf("unexpected problem: %+v", res.Problems[1])
+	}
+	if res.Unresolved("C") {
+		t.Error("expected C to be resolved")
+	}
+}
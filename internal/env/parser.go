@@ -1,146 +1,300 @@
 package env
 
 import (
-	"bufio"
 	"fmt"
 	"os"
 	"regexp"
 	"strings"
 )
 
-var refPattern = regexp.MustCompile(`\$\{?[A-Za-z_][A-Za-z0-9_]*\}?`)
+var (
+	refPattern = regexp.MustCompile(`\$\{?[A-Za-z_][A-Za-z0-9_]*\}?`)
+	keyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// Parse reads an env file and returns its entries together with any
+// syntax diagnostics found along the way.
+func Parse(path string) (File, error) {
+	return ParseDialect(path, Default)
+}
+
+// ParseDialect is like Parse but reads values the way dialect d does.
+func ParseDialect(path string, d Dialect) (File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return File{}, fmt.Errorf("cannot open %s: %w", path, err)
+	}
+
+	f := ParseStringDialect(string(data), d)
+	f.Path = path
+	return f, nil
+}
+
+// ParseString parses env file content that is already in memory.
+func ParseString(content string) File {
+	return ParseStringDialect(content, Default)
+}
+
+// ParseStringDialect parses in-memory content the way dialect d does.
+func ParseStringDialect(content string, d Dialect) File {
+	doc := ParseDocument(content, d)
+	return File{Entries: doc.Entries(), Diagnostics: doc.Diagnostics, Directives: doc.Directives}
+}
+
+// ParseDocument parses content into a lossless Document.
+func ParseDocument(content string, d Dialect) *Document {
+	raw, lines := splitLines(content)
+	p := &parser{raw: raw, lines: lines, dialect: d}
+	for i := 0; i < len(p.lines); i++ {
+		i = p.parseLine(i)
+	}
+	return &Document{
+		Nodes:       p.nodes,
+		Diagnostics: p.diags,
+		Directives:  p.directives,
+		dialect:     d,
+		newline:     detectNewline(content),
+	}
+}
 
 // ParseFile reads an env file and returns its entries.
 func ParseFile(path string) ([]Entry, error) {
-	f, err := os.Open(path)
+	f, err := Parse(path)
 	if err != nil {
-		return nil, fmt.Errorf("cannot open %s: %w", path, err)
-	}
-	defer f.Close()
-
-	var entries []Entry
-	scanner := bufio.NewScanner(f)
-	lineNum := 0
-	var multilineKey string
-	var multilineValue strings.Builder
-	var multilineStart int
-
-	for scanner.Scan() {
-		lineNum++
-		line := scanner.Text()
-
-		// Continue multiline value
-		if multilineKey != "" {
-			multilineValue.WriteString("\n")
-			multilineValue.WriteString(line)
-			if strings.HasSuffix(strings.TrimSpace(line), `"`) {
-				val := multilineValue.String()
-				// Strip surrounding quotes
-				val = strings.TrimSuffix(val, `"`)
-				entry := Entry{
-					Key:     multilineKey,
-					Value:   val,
-					LineNum: multilineStart,
-					IsRef:   refPattern.MatchString(val),
-				}
-				entries = append(entries, entry)
-				multilineKey = ""
-				multilineValue.Reset()
-			}
-			continue
-		}
+		return nil, err
+	}
+	return f.Entries, nil
+}
 
-		trimmed := strings.TrimSpace(line)
+type parser struct {
+	dialect    Dialect
+	raw        []string // lines with their terminators
+	lines      []string // lines without terminators
+	nodes      []Node
+	diags      []Diagnostic
+	directives []Directive
 
-		// Skip empty lines and full-line comments
-		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
-			continue
-		}
+	// annotations collected from the comment block above the current line
+	pending map[string]string
+}
+
+// parseLine parses the logical line starting at lines[i], appends its node
+// and returns the index of the last physical line it consumed.
+func (p *parser) parseLine(i int) int {
+	line := p.lines[i]
+	lineNum := i + 1
+	trimmed := strings.TrimSpace(line)
 
-		// Split on first '='
-		eqIdx := strings.IndexByte(trimmed, '=')
-		if eqIdx < 0 {
-			continue
+	switch {
+	case trimmed == "":
+		p.pending = nil
+		p.addNode(Node{Kind: BlankNode}, i, i)
+		return i
+	case strings.HasPrefix(trimmed, "#"):
+		comment := strings.TrimSpace(trimmed[1:])
+		if d, ok := parseDirective(comment, lineNum); ok {
+			p.directives = append(p.directives, d)
+		} else {
+			p.pending = parseAnnotations(comment, p.pending)
 		}
+		p.addNode(Node{Kind: CommentNode}, i, i)
+		return i
+	}
 
-		key := strings.TrimSpace(trimmed[:eqIdx])
-		rest := trimmed[eqIdx+1:]
+	// The comment block above belongs to this line only
+	annotations := p.pending
+	p.pending = nil
 
-		// Check for multiline start: value begins with " but doesn't end with "
-		stripped := strings.TrimSpace(rest)
-		if strings.HasPrefix(stripped, `"`) && !strings.HasSuffix(stripped, `"`) {
-			multilineKey = key
-			multilineStart = lineNum
-			multilineValue.WriteString(strings.TrimPrefix(stripped, `"`))
-			continue
-		}
+	indent := len(line) - len(strings.TrimLeft(line, " \t"))
+
+	// Split on first '='
+	eqIdx := strings.IndexByte(line, '=')
+	if eqIdx < 0 {
+		p.diag(DiagMalformedLine, "", lineNum, indent+1, "expected KEY=VALUE")
+		p.addNode(Node{Kind: InvalidNode}, i, i)
+		return i
+	}
 
-		value, comment := parseValueAndComment(rest)
-		required := strings.Contains(strings.ToLower(comment), "required")
+	key := strings.TrimSpace(line[indent:eqIdx])
+	keyCol := indent + 1
 
-		entry := Entry{
-			Key:      key,
-			Value:    value,
-			Comment:  comment,
-			LineNum:  lineNum,
-			Required: required,
-			IsRef:    refPattern.MatchString(value),
+	// Optional shell "export" prefix
+	export := false
+	if rest, ok := strings.CutPrefix(key, "export"); ok && rest != "" && (rest[0] == ' ' || rest[0] == '\t') {
+		trimmedKey := strings.TrimLeft(rest, " \t")
+		keyCol += len(key) - len(trimmedKey)
+		key, export = trimmedKey, true
+	}
+
+	switch {
+	case key == "":
+		p.diag(DiagMalformedLine, "", lineNum, indent+1, "missing key before '='")
+		p.addNode(Node{Kind: InvalidNode}, i, i)
+		return i
+	case strings.ContainsAny(key, " \t"):
+		p.diag(DiagMalformedLine, "", lineNum, keyCol, "key "+quoteSnippet(key)+" contains whitespace")
+		p.addNode(Node{Kind: InvalidNode}, i, i)
+		return i
+	case !keyPattern.MatchString(key):
+		p.diag(DiagInvalidKey, key, lineNum, keyCol, "key "+quoteSnippet(key)+" is not a valid variable name")
+	}
+
+	rest := line[eqIdx+1:]
+	raw := strings.TrimLeft(rest, " \t")
+	col := eqIdx + 1 + len(rest) - len(raw) + 1
+
+	entry := Entry{Key: key, LineNum: lineNum, Export: export}
+	start, end, endIdx := col-1, i, col-1
+
+	if raw != "" && p.dialect.isQuote(raw[0]) {
+		end, endIdx = p.parseQuoted(&entry, raw, i, col)
+	} else {
+		entry.Value, entry.Comment = p.parseUnquoted(raw)
+		endIdx += len(entry.Value)
+		if entry.Value == "" {
+			// Anchor an empty value right after '=' so setting one later
+			// keeps the spacing before an inline comment.
+			start, endIdx = eqIdx+1, eqIdx+1
 		}
-		entries = append(entries, entry)
 	}
 
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("error reading %s: %w", path, err)
+	entry.Annotations = annotations
+	mentionsRequired := false
+	if d, ok := parseDirective(entry.Comment, lineNum); ok {
+		p.directives = append(p.directives, d)
+	} else {
+		entry.Annotations = parseAnnotations(entry.Comment, annotations)
+		mentionsRequired = strings.Contains(strings.ToLower(entry.Comment), "required")
 	}
+	_, annotated := entry.Annotations["required"]
+	entry.Required = annotated || mentionsRequired
+	entry.IsRef = p.dialect.expands(entry.Quote) && refPattern.MatchString(entry.Value)
 
-	return entries, nil
+	p.addNode(Node{
+		Kind:       EntryNode,
+		Entry:      entry,
+		valueStart: start,
+		valueEnd:   p.offset(i, end, endIdx),
+	}, i, end)
+	return end
 }
 
-// parseValueAndComment splits the raw value part into the actual value and any inline comment.
-func parseValueAndComment(raw string) (string, string) {
-	raw = strings.TrimSpace(raw)
+// addNode records n as spanning lines[start] through lines[end].
+func (p *parser) addNode(n Node, start, end int) {
+	n.Raw = strings.Join(p.raw[start:end+1], "")
+	p.nodes = append(p.nodes, n)
+}
 
-	// Quoted value: find closing quote, rest is comment
-	if strings.HasPrefix(raw, `"`) {
-		end := strings.Index(raw[1:], `"`)
-		if end >= 0 {
-			value := raw[1 : end+1]
-			rest := strings.TrimSpace(raw[end+2:])
-			comment := ""
-			if strings.HasPrefix(rest, "#") {
-				comment = strings.TrimSpace(rest[1:])
-			}
-			return value, comment
-		}
+// offset converts index idx of lines[j] into a byte offset within the raw
+// text that starts at lines[i].
+func (p *parser) offset(i, j, idx int) int {
+	n := idx
+	for k := i; k < j; k++ {
+		n += len(p.raw[k])
 	}
-	if strings.HasPrefix(raw, `'`) {
-		end := strings.Index(raw[1:], `'`)
-		if end >= 0 {
-			value := raw[1 : end+1]
-			rest := strings.TrimSpace(raw[end+2:])
-			comment := ""
-			if strings.HasPrefix(rest, "#") {
-				comment = strings.TrimSpace(rest[1:])
-			}
-			return value, comment
+	return n
+}
+
+// parseQuoted reads a quoted value starting at raw, which sits at column col
+// of lines[i]. It returns the index of the line holding the closing quote
+// and the index just past that quote within the line.
+func (p *parser) parseQuoted(entry *Entry, raw string, i, col int) (int, int) {
+	quote := raw[0]
+	entry.Quote = quote
+
+	var value strings.Builder
+	text, textCol := raw[1:], col+1
+	j := i
+	for {
+		if end := p.dialect.closingQuote(text, quote); end >= 0 {
+			value.WriteString(text[:end])
+			entry.Value = p.dialect.unescape(value.String(), quote)
+			p.trailing(entry, text[end+1:], j+1, textCol+end+1)
+			return j, textCol + end
+		}
+		value.WriteString(text)
+
+		// Multiline value: continue on the next line
+		if !p.dialect.isMultiline(quote) || j+1 == len(p.lines) {
+			break
 		}
+		j++
+		value.WriteString("\n")
+		text, textCol = p.lines[j], 1
 	}
 
-	// Value starts with # → entire thing is a comment (empty value)
-	if strings.HasPrefix(raw, "#") {
-		return "", strings.TrimSpace(raw[1:])
+	entry.Value = p.dialect.unescape(value.String(), quote)
+	p.diag(DiagUnterminatedQuote, entry.Key, i+1, col, "unterminated "+quoteName(quote)+" value")
+	return j, len(p.lines[j])
+}
+
+// trailing handles whatever follows a closing quote: nothing, an inline
+// comment, or garbage. rest starts at column col of line lineNum.
+func (p *parser) trailing(entry *Entry, rest string, lineNum, col int) {
+	t := strings.TrimLeft(rest, " \t")
+	switch {
+	case t == "":
+	case strings.HasPrefix(t, "#"):
+		entry.Comment = strings.TrimSpace(t[1:])
+	default:
+		p.diag(DiagTrailingGarbage, entry.Key, lineNum, col+len(rest)-len(t), "unexpected text after closing quote")
 	}
+}
 
-	// Unquoted: split on first # that has a space before it
-	if idx := strings.Index(raw, " #"); idx >= 0 {
+func (p *parser) diag(kind DiagnosticKind, key string, line, col int, msg string) {
+	p.diags = append(p.diags, Diagnostic{Kind: kind, Key: key, Line: line, Column: col, Message: msg})
+}
+
+// parseUnquoted splits an unquoted raw value into the value and any inline comment.
+func (p *parser) parseUnquoted(raw string) (string, string) {
+	raw = strings.TrimSpace(raw)
+
+	// A # at the start, or after whitespace, begins the comment
+	if idx := p.dialect.commentStart(raw); idx >= 0 {
 		value := strings.TrimSpace(raw[:idx])
-		comment := strings.TrimSpace(raw[idx+2:])
+		comment := strings.TrimSpace(raw[idx+1:])
 		return value, comment
 	}
 
 	return raw, ""
 }
 
+func quoteName(quote byte) string {
+	switch quote {
+	case '"':
+		return "double-quoted"
+	case '\'':
+		return "single-quoted"
+	default:
+		return "backtick-quoted"
+	}
+}
+
+// splitLines splits content into lines, both with and without their
+// line terminators.
+func splitLines(content string) (raw, lines []string) {
+	for content != "" {
+		n := strings.IndexByte(content, '\n') + 1
+		if n == 0 {
+			n = len(content)
+		}
+		line := content[:n]
+		raw = append(raw, line)
+		lines = append(lines, strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r"))
+		content = content[n:]
+	}
+	return raw, lines
+}
+
+// quoteSnippet quotes s for use in diagnostic messages, shortening long input.
+func quoteSnippet(s string) string {
+	const max = 40
+	if len(s) > max {
+		s = s[:max] + "…"
+	}
+	return fmt.Sprintf("%q", s)
+}
+
 // ParseEntries builds a map from key to Entry for quick lookup.
 func ParseEntries(entries []Entry) map[string]Entry {
 	m := make(map[string]Entry, len(entries))
@@ -3,6 +3,7 @@ package env
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 )
 
@@ -157,3 +158,183 @@ func TestParseFileNotFound(t *testing.T) {
 		t.Error("expected error for nonexistent file")
 	}
 }
+
+func TestParseDiagnostics(t *testing.T) {
+	path := writeTmp(t, `GOOD=1
+DATABASE_URL postgres://localhost/db
+my.key=value
+QUOTED="value" trailing
+=nokey
+`)
+	f, err := Parse(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Diagnostic{
+		{Kind: DiagMalformedLine, Line: 2, Column: 1},
+		{Kind: DiagInvalidKey, Line: 3, Column: 1},
+		{Kind: DiagTrailingGarbage, Line: 4, Column: 16},
+		{Kind: DiagMalformedLine, Line: 5, Column: 1},
+	}
+	if len(f.Diagnostics) != len(want) {
+		t.Fatalf("expected %d diagnostics, got %v", len(want), f.Diagnostics)
+	}
+	for i, w := range want {
+		d := f.Diagnostics[i]
+		if d.Kind != w.Kind || d.Line != w.Line || d.Column != w.Column {
+			t.Errorf("diagnostic %d: expected %s at %d:%d, got %s at %d:%d",
+				i, w.Kind, w.Line, w.Column, d.Kind, d.Line, d.Column)
+		}
+	}
+
+	// Invalid key names and trailing garbage keep their entry
+	if len(f.Entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(f.Entries))
+	}
+	if f.Entries[2].Value != "value" {
+		t.Errorf("expected quoted value to survive trailing garbage, got %q", f.Entries[2].Value)
+	}
+}
+
+func TestParseUnterminatedQuote(t *testing.T) {
+	path := writeTmp(t, "FIRST=1\nCERT=\"-----BEGIN\nabc\n")
+	f, err := Parse(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Diagnostics) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %v", f.Diagnostics)
+	}
+	d := f.Diagnostics[0]
+	if d.Kind != DiagUnterminatedQuote || d.Line != 2 || d.Column != 6 {
+		t.Errorf("unexpected diagnostic: %+v", d)
+	}
+	if len(f.Entries) != 2 || f.Entries[1].Key != "CERT" {
+		t.Errorf("expected unterminated entry to be kept, got %+v", f.Entries)
+	}
+}
+
+func TestParseQuotedValueWithComment(t *testing.T) {
+	path := writeTmp(t, "A=\"foo\" # note\nB=bar\n")
+	f, err := Parse(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Diagnostics) != 0 {
+		t.Fatalf("expected no diagnostics, got %v", f.Diagnostics)
+	}
+	if len(f.Entries) != 2 || f.Entries[0].Value != "foo" || f.Entries[0].Comment != "note" {
+		t.Errorf("unexpected entries: %+v", f.Entries)
+	}
+}
+
+func TestParseExportPrefix(t *testing.T) {
+	path := writeTmp(t, "export FOO=bar\nBAR=baz\nexport=literal\n  export   QUX=1\n")
+	f, err := Parse(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Diagnostics) != 0 {
+		t.Fatalf("expected no diagnostics, got %v", f.Diagnostics)
+	}
+
+	want := []struct {
+		key    string
+		export bool
+	}{
+		{"FOO", true},
+		{"BAR", false},
+		{"export", false},
+		{"QUX", true},
+	}
+	if len(f.Entries) != len(want) {
+		t.Fatalf("expected %d entries, got %d", len(want), len(f.Entries))
+	}
+	for i, w := range want {
+		if f.Entries[i].Key != w.key || f.Entries[i].Export != w.export {
+			t.Errorf("entry %d: expected %s (export=%v), got %s (export=%v)",
+				i, w.key, w.export, f.Entries[i].Key, f.Entries[i].Export)
+		}
+	}
+}
+
+func TestParseAnnotations(t *testing.T) {
+	path := writeTmp(t, `# Request timeout
+# @type=int @min=1
+TIMEOUT_MS=500 # @max=100
+LOG_LEVEL=info # one of @enum=debug,info,warn
+# @pattern="^sk_[a-z]+$" @required
+
+API_KEY=
+# @secret
+TOKEN=
+PLAIN=1 # user@example.com
+`)
+	entries, err := ParseFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := ParseEntries(entries)
+
+	timeout := m["TIMEOUT_MS"].Annotations
+	if timeout["type"] != "int" || timeout["min"] != "1" || timeout["max"] != "100" {
+		t.Errorf("unexpected TIMEOUT_MS annotations: %v", timeout)
+	}
+	if got := m["LOG_LEVEL"].Annotations["enum"]; got != "debug,info,warn" {
+		t.Errorf("unexpected enum: %q", got)
+	}
+	if m["API_KEY"].Annotations != nil || m["API_KEY"].Required {
+		t.Errorf("a blank line should detach the comment block, got %v", m["API_KEY"].Annotations)
+	}
+	if _, ok := m["TOKEN"].Annotations["secret"]; !ok {
+		t.Errorf("expected bare @secret annotation, got %v", m["TOKEN"].Annotations)
+	}
+	if m["PLAIN"].Annotations != nil {
+		t.Errorf("email addresses are not annotations, got %v", m["PLAIN"].Annotations)
+	}
+}
+
+func TestParseAnnotationRequired(t *testing.T) {
+	path := writeTmp(t, "# @required @pattern=\"^a b$\"\nKEY=\n")
+	entries, err := ParseFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !entries[0].Required {
+		t.Error("expected @required to mark the key required")
+	}
+	if entries[0].Annotations["pattern"] != "^a b$" {
+		t.Errorf("expected quoted pattern, got %q", entries[0].Annotations["pattern"])
+	}
+}
+
+func TestParseDirectives(t *testing.T) {
+	f := ParseString(`# envlint-disable-next-line invalid-url
+API_URL=not-a-url
+DB_PORT=abc # envlint-disable-line invalid-port, invalid-url -- legacy value
+# envlint-disable
+LEGACY=1 # envlint-disable-line required-empty
+# envlint-enable
+# a plain comment
+`)
+
+	want := []Directive{
+		{Kind: DisableNextLine, Rules: []string{"invalid-url"}, Line: 1},
+		{Kind: DisableLine, Rules: []string{"invalid-port", "invalid-url"}, Line: 3},
+		{Kind: Disable, Rules: []string{}, Line: 4},
+		{Kind: DisableLine, Rules: []string{"required-empty"}, Line: 5},
+		{Kind: Enable, Rules: []string{}, Line: 6},
+	}
+	if len(f.Directives) != len(want) {
+		t.Fatalf("expected %d directives, got %+v", len(want), f.Directives)
+	}
+	for i, d := range f.Directives {
+		if d.Kind != want[i].Kind || d.Line != want[i].Line || !slices.Equal(d.Rules, want[i].Rules) {
+			t.Errorf("directive %d: expected %+v, got %+v", i, want[i], d)
+		}
+	}
+	if m := ParseEntries(f.Entries); m["LEGACY"].Required {
+		t.Error("a directive naming required-empty must not mark the key required")
+	}
+}
@@ -13,6 +13,17 @@ func InGitRepo() bool {
 	return err == nil && strings.TrimSpace(string(out)) == "true"
 }
 
+// RepoRoot returns the top-level directory of the git repository that
+// contains the current directory, or "" outside a repository.
+func RepoRoot() string {
+	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
 // IsIgnored checks if a file path is listed in .gitignore (tracked by git's check-ignore).
 func IsIgnored(path string) bool {
 	cmd := exec.Command("git", "check-ignore", "-q", path)
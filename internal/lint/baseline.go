@@ -0,0 +1,125 @@
+package lint
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+const baselineVersion = 1
+
+// Baseline records known issues by rule, key and file. Lines are left out
+// so the baseline survives edits that move keys around.
+type Baseline struct {
+	Version int             `json:"version"`
+	Issues  []BaselineEntry `json:"issues"`
+}
+
+// BaselineEntry is a known issue. Count is how many times it occurs, for
+// issues such as duplicate-key that repeat for the same key.
+type BaselineEntry struct {
+	Rule  string `json:"rule"`
+	Key   string `json:"key,omitempty"`
+	File  string `json:"file"`
+	Count int    `json:"count"`
+}
+
+func (e BaselineEntry) matches(issue Issue) bool {
+	return e.Rule == issue.Rule && e.Key == issue.Key && e.File == baselinePath(issue.File)
+}
+
+func compareBaselineEntries(a, b BaselineEntry) int {
+	return cmp.Or(
+		cmp.Compare(a.File, b.File),
+		cmp.Compare(a.Rule, b.Rule),
+		cmp.Compare(a.Key, b.Key),
+	)
+}
+
+// NewBaseline records the issues of r.
+func NewBaseline(r Result) Baseline {
+	b := Baseline{Version: baselineVersion, Issues: []BaselineEntry{}}
+	for _, issue := range r.Issues {
+		i := slices.IndexFunc(b.Issues, func(e BaselineEntry) bool { return e.matches(issue) })
+		if i < 0 {
+			b.Issues = append(b.Issues, BaselineEntry{Rule: issue.Rule, Key: issue.Key, File: baselinePath(issue.File)})
+			i = len(b.Issues) - 1
+		}
+		b.Issues[i].Count++
+	}
+	slices.SortFunc(b.Issues, compareBaselineEntries)
+	return b
+}
+
+// ReadBaseline reads a baseline file written by WriteBaseline.
+func ReadBaseline(path string) (Baseline, error) {
+	var b Baseline
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return b, fmt.Errorf("cannot open baseline %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, &b); err != nil {
+		return b, fmt.Errorf("invalid baseline %s: %w", path, err)
+	}
+	if b.Version != baselineVersion {
+		return b, fmt.Errorf("baseline %s has unsupported version %d", path, b.Version)
+	}
+	for i, e := range b.Issues {
+		b.Issues[i].File = baselinePath(e.File)
+	}
+	return b, nil
+}
+
+// WriteBaseline writes b to path as indented JSON.
+func WriteBaseline(path string, b Baseline) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// Apply moves the issues of r that the baseline knows about to
+// r.Baselined and records in r.Fixed the baseline entries of files that
+// no longer occur. Only entries of the given files are considered fixed,
+// so linting a subset of files does not report the others.
+func (b Baseline) Apply(r *Result, files ...string) {
+	remaining := make([]int, len(b.Issues))
+	for i, e := range b.Issues {
+		remaining[i] = e.Count
+	}
+
+	kept := r.Issues[:0]
+	for _, issue := range r.Issues {
+		i := slices.IndexFunc(b.Issues, func(e BaselineEntry) bool { return e.matches(issue) })
+		if i >= 0 && remaining[i] > 0 {
+			remaining[i]--
+			r.Baselined = append(r.Baselined, issue)
+			continue
+		}
+		kept = append(kept, issue)
+	}
+	r.Issues = kept
+
+	checked := make(map[string]bool, len(files))
+	for _, f := range files {
+		checked[baselinePath(f)] = true
+	}
+	for i, e := range b.Issues {
+		if remaining[i] > 0 && checked[e.File] {
+			e.Count = remaining[i]
+			r.Fixed = append(r.Fixed, e)
+		}
+	}
+}
+
+// baselinePath normalizes a file path so baselines are portable.
+func baselinePath(path string) string {
+	if path == "" {
+		return ""
+	}
+	return filepath.ToSlash(filepath.Clean(path))
+}
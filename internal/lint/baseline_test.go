@@ -0,0 +1,56 @@
+package lint
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestBaselineApply(t *testing.T) {
+	before := Result{Issues: []Issue{
+		{Rule: "invalid-url", Key: "API_URL", File: ".env", LineNum: 1},
+		{Rule: "duplicate-key", Key: "A", File: ".env", LineNum: 2},
+		{Rule: "duplicate-key", Key: "A", File: ".env", LineNum: 5},
+		{Rule: "extra-key", Key: "OLD", File: ".env", LineNum: 3},
+		{Rule: "extra-key", Key: "LOCAL", File: ".env.local", LineNum: 1},
+	}}
+	b := NewBaseline(before)
+	if len(b.Issues) != 4 {
+		t.Fatalf("expected 4 entries, got %+v", b.Issues)
+	}
+
+	// Keys moved to other lines, OLD was removed and a new issue appeared
+	after := Result{Issues: []Issue{
+		{Rule: "invalid-url", Key: "API_URL", File: ".env", LineNum: 7},
+		{Rule: "duplicate-key", Key: "A", File: ".env", LineNum: 1},
+		{Rule: "duplicate-key", Key: "A", File: ".env", LineNum: 2},
+		{Rule: "duplicate-key", Key: "A", File: ".env", LineNum: 3},
+		{Rule: "missing-key", Key: "NEW", File: ".env"},
+	}}
+	b.Apply(&after, ".env", ".env.example")
+
+	if len(after.Baselined) != 3 {
+		t.Errorf("expected 3 baselined issues, got %+v", after.Baselined)
+	}
+	if len(after.Issues) != 2 || after.Issues[0].Rule != "duplicate-key" || after.Issues[1].Key != "NEW" {
+		t.Errorf("expected the third duplicate and NEW to be reported, got %+v", after.Issues)
+	}
+	if len(after.Fixed) != 1 || after.Fixed[0].Key != "OLD" {
+		t.Errorf("expected OLD to be fixed and .env.local to be skipped, got %+v", after.Fixed)
+	}
+}
+
+func TestBaselineRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".envlint-baseline.json")
+	b := NewBaseline(Result{Issues: []Issue{{Rule: "extra-key", Key: "X", File: "./config/.env"}}})
+	if err := WriteBaseline(path, b); err != nil {
+		t.Fatal(err)
+	}
+
+	read, err := ReadBaseline(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(read.Issues) != 1 || read.Issues[0].File != "config/.env" || read.Issues[0].Count != 1 {
+		t.Errorf("unexpected baseline: %+v", read)
+	}
+}
@@ -0,0 +1,100 @@
+package lint
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/rasalas/envlint/internal/env"
+)
+
+// Fix applies the fixes of issues to doc, the parsed env file the issues
+// were reported on, and returns the issues it fixed. Issues of other files
+// are skipped, and unsafe fixes are only applied when unsafe is set.
+// Missing keys are added with the example value, after the key that
+// precedes them in the example.
+func Fix(doc *env.Document, path string, exampleFile env.File, issues []Issue, opts Options, unsafe bool) []Issue {
+	var fixed []Issue
+	done := make(map[string]bool) // rule and key of fixes already applied
+
+	for _, issue := range issues {
+		if issue.File != path || issue.Fix == FixNone || (issue.Fix == FixUnsafe && !unsafe) {
+			continue
+		}
+		id := issue.Rule + "\x00" + issue.Key
+		if done[id] {
+			fixed = append(fixed, issue)
+			continue
+		}
+
+		ok := false
+		switch issue.Rule {
+		case "missing-key":
+			ok = addMissingKey(doc, exampleFile, issue.Key)
+		case "duplicate-key":
+			ok = doc.Dedupe(issue.Key, opts.FirstWins) > 0
+		case "export-prefix":
+			ok = doc.SetExport(issue.Key, issue.Detail == missingExportDetail) > 0
+		case "extra-key":
+			ok = doc.CommentOut(issue.Key) > 0
+		case "invalid-boolean":
+			if v, found := docValue(doc, issue.Key); found {
+				if b, known := booleanWords[strings.ToLower(strings.TrimSpace(v))]; known {
+					doc.Set(issue.Key, b)
+					ok = true
+				}
+			}
+		case "surrounding-whitespace":
+			if v, found := docValue(doc, issue.Key); found {
+				doc.Set(issue.Key, strings.Trim(v, " \t"))
+				ok = true
+			}
+		}
+		if ok {
+			done[id] = true
+			fixed = append(fixed, issue)
+		}
+	}
+	return fixed
+}
+
+// FixableCount returns how many issues have a fix, counting unsafe fixes
+// only when unsafe is set.
+func (r Result) FixableCount(unsafe bool) int {
+	n := 0
+	for _, issue := range r.Issues {
+		if issue.Fix == FixSafe || (issue.Fix == FixUnsafe && unsafe) {
+			n++
+		}
+	}
+	return n
+}
+
+// addMissingKey adds key with its example value after the closest key
+// that precedes it in the example and exists in doc.
+func addMissingKey(doc *env.Document, exampleFile env.File, key string) bool {
+	i := slices.IndexFunc(exampleFile.Entries, func(e env.Entry) bool { return e.Key == key })
+	if i < 0 || doc.Has(key) {
+		return false
+	}
+	ex := exampleFile.Entries[i]
+	entry := env.Entry{Key: key, Value: ex.Value, Quote: ex.Quote, Export: ex.Export}
+
+	for j := i - 1; j >= 0; j-- {
+		if after := exampleFile.Entries[j].Key; doc.Has(after) {
+			return doc.InsertAfter(after, entry) == nil
+		}
+	}
+	doc.Append(entry)
+	return true
+}
+
+// docValue returns the value of the last definition of key in doc.
+func docValue(doc *env.Document, key string) (string, bool) {
+	entries := doc.Entries()
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].Key == key {
+			return entries[i].Value, true
+		}
+	}
+	return "", false
+}
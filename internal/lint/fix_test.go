@@ -0,0 +1,49 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/rasalas/envlint/internal/env"
+)
+
+func TestFix(t *testing.T) {
+	example := env.ParseString("# app\nAPP_NAME=demo\nAPP_PORT=8080\nIS_ENABLED=true\nDEBUG_ENABLED=false\n\n# db\nDB_HOST=localhost\nDB_NAME=app\n")
+	content := "# local\nexport APP_NAME=demo\nAPP_PORT=8080\nIS_ENABLED=Yes please\nAPP_PORT=9090\nDB_HOST=\" db \" # trimmed\nLEGACY=1\nDEBUG_ENABLED=y\n"
+	opts := Options{ExportPrefix: "forbid"}
+
+	check := func(doc *env.Document) Result {
+		f := env.ParseString(doc.String())
+		f.Path = ".env"
+		return CheckFiles(example, f, opts)
+	}
+
+	doc := env.ParseDocument(content, env.Default)
+	fixed := Fix(doc, ".env", example, check(doc).Issues, opts, false)
+	if len(fixed) != 4 {
+		t.Errorf("expected 4 safe fixes, got %+v", fixed)
+	}
+	want := "# local\nAPP_NAME=demo\nIS_ENABLED=Yes please\nAPP_PORT=9090\nDB_HOST=\" db \" # trimmed\nDB_NAME=app\nLEGACY=1\nDEBUG_ENABLED=y\n"
+	if got := doc.String(); got != want {
+		t.Errorf("unexpected safe fix:\n got  %q\n want %q", got, want)
+	}
+
+	Fix(doc, ".env", example, check(doc).Issues, opts, true)
+	want = "# local\nAPP_NAME=demo\nIS_ENABLED=Yes please\nAPP_PORT=9090\nDB_HOST=\"db\" # trimmed\nDB_NAME=app\n# LEGACY=1\nDEBUG_ENABLED=true\n"
+	if got := doc.String(); got != want {
+		t.Errorf("unexpected unsafe fix:\n got  %q\n want %q", got, want)
+	}
+
+	// What is left has no fix
+	left := check(doc)
+	if left.FixableCount(true) != 0 || len(left.ByRule("invalid-boolean")) != 1 {
+		t.Errorf("expected only the unfixable boolean to remain, got %+v", left.Issues)
+	}
+}
+
+func TestFixSkipsOtherFiles(t *testing.T) {
+	doc := env.ParseDocument("A=1\n", env.Default)
+	issues := []Issue{{Rule: "missing-key", Key: "B", File: ".env.example", Fix: FixSafe}}
+	if fixed := Fix(doc, ".env", env.ParseString("A=\nB=2\n"), issues, Options{}, true); len(fixed) != 0 {
+		t.Errorf("expected issues of other files to be skipped, got %+v", fixed)
+	}
+}
@@ -1,50 +1,132 @@
 package lint
 
-import "github.com/rasalas/envlint/internal/env"
+import (
+	"regexp"
+
+	"github.com/rasalas/envlint/internal/env"
+)
 
 // Options configures linter behavior.
 type Options struct {
-	Strict      bool
-	NoExtra     bool
-	StrictURLs  bool
-	StrictPorts bool
+	Strict       bool
+	NoExtra      bool
+	StrictURLs   bool
+	StrictPorts  bool
 	RequiredKeys []string
 	IgnoreKeys   []string
+
+	// FirstWins resolves duplicate keys to their first definition instead
+	// of the last, matching loaders that never overwrite a key.
+	FirstWins bool
+
+	// ExportPrefix is "require", "forbid" or "consistent" to enforce the
+	// shell "export" prefix; empty allows both styles.
+	ExportPrefix string
+
+	// Keys holds per-key specs from the config. They take precedence over
+	// annotations in the example file.
+	Keys map[string]KeySpec
+
+	// Severity overrides the default severity of rules by ID. SeverityOff
+	// disables a rule.
+	Severity map[string]Severity
+
+	// SecretKeys and SecretAllow exempt keys and values matching one of
+	// the patterns from secret-in-example.
+	SecretKeys  []string
+	SecretAllow []*regexp.Regexp
+
+	// Placeholders are patterns for placeholder values in addition to
+	// the built-in ones. Like those, they should be anchored.
+	Placeholders []*regexp.Regexp
+
+	// Lookup resolves references to variables the env file does not
+	// define, typically os.LookupEnv. Nil leaves them undefined.
+	Lookup func(string) (string, bool)
 }
 
 // Check runs all lint rules against the given env entries.
 func Check(exampleEntries, envEntries []env.Entry, opts Options) Result {
-	example := env.ParseEntries(exampleEntries)
-	actual := env.ParseEntries(envEntries)
+	return CheckFiles(env.File{Entries: exampleEntries}, env.File{Entries: envEntries}, opts)
+}
+
+// CheckFiles runs every registered rule against parsed env files,
+// including the syntax diagnostics the parser found in either of them.
+func CheckFiles(exampleFile, envFile env.File, opts Options) Result {
+	ctx := NewContext(exampleFile, envFile, opts)
 
 	var result Result
 
 	// Count total unique keys
 	allKeys := make(map[string]bool)
-	for k := range example {
+	for k := range ctx.Example {
 		allKeys[k] = true
 	}
-	for k := range actual {
+	for k := range ctx.Actual {
 		allKeys[k] = true
 	}
 	result.SetTotalKeys(len(allKeys))
 
-	// Run all rules
-	result.addAll(checkMissingKeys(example, actual, opts))
+	for _, rule := range registry {
+		result.addAll(withSeverity(rule.ID(), rule.Check(ctx), opts))
+	}
+	result.SetFile(envFile.Path)
 
-	extras := checkExtraKeys(example, actual, opts)
-	if opts.NoExtra {
-		for i := range extras {
-			extras[i].Severity = SeverityError
+	// Apply inline suppressions once every rule has run
+	sups := append(suppressions(exampleFile, true), suppressions(envFile, false)...)
+	result.suppress(sups)
+	result.addAll(withSeverity("unused-suppression", checkUnusedSuppressions(sups), opts))
+	for _, s := range sups {
+		if s.example && s.used {
+			result.usedExample = append(result.usedExample, directiveAt{s.file, s.directive.Line})
 		}
 	}
-	result.addAll(extras)
+	result.Sort()
+	return result
+}
 
-	result.addAll(checkRequiredEmpty(example, actual, opts))
-	result.addAll(checkURLFormat(actual, opts))
-	result.addAll(checkPortFormat(actual, opts))
-	result.addAll(checkEmailFormat(actual, opts))
-	result.addAll(checkBooleanFormat(actual, opts))
+// withSeverity applies the configured severity of rule to its issues and
+// drops them when the rule is off.
+func withSeverity(rule string, issues []Issue, opts Options) []Issue {
+	sev, configured := opts.Severity[rule]
+	if !configured {
+		return issues
+	}
+	if sev == SeverityOff {
+		return nil
+	}
+	for i := range issues {
+		issues[i].Severity = sev
+	}
+	return issues
+}
 
-	return result
+// indexEntries builds a key lookup in which duplicate keys resolve the way
+// the configured loader would.
+func indexEntries(entries []env.Entry, opts Options) map[string]env.Entry {
+	if !opts.FirstWins {
+		return env.ParseEntries(entries)
+	}
+	m := make(map[string]env.Entry, len(entries))
+	for _, e := range entries {
+		if _, ok := m[e.Key]; !ok {
+			m[e.Key] = e
+		}
+	}
+	return m
+}
+
+// expandEntries returns a copy of entries whose references are replaced by
+// their expanded values. Values with unresolved references keep IsRef so
+// value rules skip them.
+func expandEntries(entries map[string]env.Entry, res env.Resolution) map[string]env.Entry {
+	out := make(map[string]env.Entry, len(entries))
+	for k, e := range entries {
+		if e.IsRef && !res.Unresolved(k) {
+			e.Value = res.Values[k]
+			e.IsRef = false
+		}
+		out[k] = e
+	}
+	return out
 }
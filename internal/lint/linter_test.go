@@ -1,6 +1,7 @@
 package lint
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/rasalas/envlint/internal/env"
@@ -80,3 +81,181 @@ func TestPromoteWarnings(t *testing.T) {
 		}
 	}
 }
+
+func TestMergeResults(t *testing.T) {
+	a := Result{Issues: []Issue{{Rule: "missing-key", Key: "A", Severity: SeverityError}}}
+	a.SetTotalKeys(2)
+	a.SetFile(".env")
+
+	b := Result{Issues: []Issue{{Rule: "extra-key", Key: "B", Severity: SeverityWarning}}}
+	b.SetTotalKeys(3)
+	b.SetFile(".env.local")
+
+	a.Merge(b)
+	if a.TotalKeys() != 5 {
+		t.Errorf("expected 5 total keys, got %d", a.TotalKeys())
+	}
+	if len(a.Issues) != 2 {
+		t.Fatalf("expected 2 issues, got %d", len(a.Issues))
+	}
+	if a.Issues[0].File != ".env" || a.Issues[1].File != ".env.local" {
+		t.Errorf("unexpected files: %q, %q", a.Issues[0].File, a.Issues[1].File)
+	}
+}
+
+func TestCheckIssuesAreSorted(t *testing.T) {
+	example := []env.Entry{
+		{Key: "B_URL", Value: "http://x", LineNum: 1},
+		{Key: "A_PORT", Value: "80", LineNum: 2},
+		{Key: "C_MISSING", Value: "", LineNum: 3},
+		{Key: "D_MISSING", Value: "", LineNum: 4},
+	}
+	envEntries := []env.Entry{
+		{Key: "EXTRA", Value: "1", LineNum: 1},
+		{Key: "A_PORT", Value: "abc", LineNum: 2},
+		{Key: "B_URL", Value: "nope", LineNum: 5},
+	}
+	opts := Options{StrictURLs: true, StrictPorts: true}
+
+	for range 20 {
+		result := Check(example, envEntries, opts)
+		var got []string
+		for _, issue := range result.Issues {
+			got = append(got, issue.Rule+":"+issue.Key)
+		}
+		want := []string{"extra-key:EXTRA", "invalid-port:A_PORT", "missing-key:C_MISSING", "missing-key:D_MISSING", "invalid-url:B_URL"}
+		if !slices.Equal(got, want) {
+			t.Fatalf("unexpected order:\n got  %v\n want %v", got, want)
+		}
+	}
+}
+
+func TestCheckFilesReportsSyntax(t *testing.T) {
+	exampleFile := env.ParseString("A=1\nB=\"x\" junk\n")
+	exampleFile.Path = ".env.example"
+	envFile := env.ParseString("A=1\nB=2\nDATABASE_URL postgres://localhost\n")
+	envFile.Path = ".env"
+
+	result := CheckFiles(exampleFile, envFile, Options{})
+
+	syntax := result.SyntaxIssues()
+	if len(syntax) != 2 {
+		t.Fatalf("expected 2 syntax issues, got %+v", syntax)
+	}
+	if syntax[0].File != ".env" || syntax[0].LineNum != 3 {
+		t.Errorf("expected env file issue at line 3, got %+v", syntax[0])
+	}
+	if syntax[1].File != ".env.example" || syntax[1].Key != "B" {
+		t.Errorf("expected example file issue for B, got %+v", syntax[1])
+	}
+	if len(result.ValueIssues()) != 0 {
+		t.Errorf("expected no value issues, got %+v", result.ValueIssues())
+	}
+}
+
+func TestCheckFirstWinsLookup(t *testing.T) {
+	example := []env.Entry{{Key: "APP_PORT", Value: "3000"}}
+	envEntries := []env.Entry{
+		{Key: "APP_PORT", Value: "abc", LineNum: 1},
+		{Key: "APP_PORT", Value: "8080", LineNum: 2},
+	}
+
+	result := Check(example, envEntries, Options{StrictPorts: true})
+	if len(result.ByRule("invalid-port")) != 0 {
+		t.Error("expected last definition to be validated")
+	}
+
+	result = Check(example, envEntries, Options{StrictPorts: true, FirstWins: true})
+	if len(result.ByRule("invalid-port")) != 1 {
+		t.Error("expected first definition to be validated")
+	}
+	if len(result.ByRule("duplicate-key")) != 2 {
+		t.Errorf("expected 2 duplicate-key issues, got %d", len(result.ByRule("duplicate-key")))
+	}
+}
+
+func TestCheckValidatesExpandedValues(t *testing.T) {
+	example := env.ParseString("BASE_URL=http://localhost\nAPI_URL=http://localhost/api\nPORT=3000\n")
+	envFile := env.ParseString("BASE_URL=localhost\nAPI_URL=${BASE_URL}/api\nPORT=${APP_PORT}\n")
+
+	result := CheckFiles(example, envFile, Options{StrictURLs: true, StrictPorts: true})
+
+	urls := result.ByRule("invalid-url")
+	if len(urls) != 2 {
+		t.Errorf("expected BASE_URL and expanded API_URL to be invalid, got %+v", urls)
+	}
+	if len(result.ByRule("undefined-reference")) != 1 {
+		t.Errorf("expected undefined APP_PORT, got %+v", result.Issues)
+	}
+	if len(result.ByRule("invalid-port")) != 0 {
+		t.Error("unresolved values should not be validated")
+	}
+
+	lookup := func(name string) (string, bool) { return "99999", name == "APP_PORT" }
+	result = CheckFiles(example, envFile, Options{StrictPorts: true, Lookup: lookup})
+	if len(result.ByRule("invalid-port")) != 1 {
+		t.Errorf("expected port from lookup to be validated, got %+v", result.Issues)
+	}
+}
+
+func TestCheckReferenceDefaults(t *testing.T) {
+	example := env.ParseString("API_KEY= # required\nTOKEN= # required\nSECRET= # required\n")
+	envFile := env.ParseString("API_KEY=${VAULT_KEY:-dev-key}\nTOKEN=${VAULT_TOKEN:-}\nSECRET=${VAULT_SECRET:?run make secrets}\n")
+
+	result := CheckFiles(example, envFile, Options{})
+
+	empty := result.ByRule("required-empty")
+	if len(empty) != 1 || empty[0].Key != "TOKEN" {
+		t.Errorf("expected only TOKEN to be required-empty, got %+v", empty)
+	}
+	errs := result.ByRule("reference-error")
+	if len(errs) != 1 || errs[0].Detail != "VAULT_SECRET: run make secrets" || errs[0].Severity != SeverityError {
+		t.Errorf("unexpected reference-error issues: %+v", errs)
+	}
+	if len(result.ByRule("undefined-reference")) != 0 {
+		t.Errorf("references with defaults are not undefined, got %+v", result.Issues)
+	}
+}
+
+func TestCheckSeverityOverrides(t *testing.T) {
+	example := env.ParseString("ADMIN_EMAIL=\nIS_ENABLED=\n")
+	envFile := env.ParseString("ADMIN_EMAIL=nope\nIS_ENABLED=maybe\nEXTRA=1\n")
+
+	result := CheckFiles(example, envFile, Options{
+		NoExtra: true,
+		Severity: map[string]Severity{
+			"invalid-email":   SeverityError,
+			"invalid-boolean": SeverityInfo,
+			"extra-key":       SeverityOff,
+		},
+	})
+
+	if email := result.ByRule("invalid-email"); len(email) != 1 || email[0].Severity != SeverityError {
+		t.Errorf("expected invalid-email as error, got %+v", email)
+	}
+	if boolean := result.ByRule("invalid-boolean"); len(boolean) != 1 || boolean[0].Severity != SeverityInfo {
+		t.Errorf("expected invalid-boolean as info, got %+v", boolean)
+	}
+	if len(result.ByRule("extra-key")) != 0 {
+		t.Errorf("extra-key is off, got %+v", result.ByRule("extra-key"))
+	}
+	if result.ErrorCount() != 1 || result.WarnCount() != 0 || result.InfoCount() != 1 {
+		t.Errorf("unexpected counts: %d errors, %d warnings, %d info", result.ErrorCount(), result.WarnCount(), result.InfoCount())
+	}
+
+	result.PromoteWarnings()
+	if result.InfoCount() != 1 {
+		t.Error("strict mode should not promote info issues")
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	for _, s := range []string{"error", "warning", "info", "off"} {
+		if _, ok := ParseSeverity(s); !ok {
+			t.Errorf("expected %q to parse", s)
+		}
+	}
+	if _, ok := ParseSeverity("fatal"); ok {
+		t.Error("expected fatal to be rejected")
+	}
+}
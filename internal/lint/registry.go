@@ -0,0 +1,195 @@
+package lint
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/rasalas/envlint/internal/env"
+)
+
+// Rule is a single lint check, identified by the rule name its issues carry.
+type Rule interface {
+	ID() string
+	DefaultSeverity() Severity
+	Description() string
+	// Check returns the issues of this rule only.
+	Check(ctx *Context) []Issue
+}
+
+// Context is the input shared by all rules for one env file.
+type Context struct {
+	ExampleFile env.File
+	EnvFile     env.File
+
+	// Example and Actual index the keys of both files with duplicates
+	// resolved per Options.FirstWins. Actual values have their references
+	// expanded.
+	Example map[string]env.Entry
+	Actual  map[string]env.Entry
+
+	Resolution env.Resolution
+	// Specs merges the example annotations with Options.Keys.
+	Specs   map[string]KeySpec
+	Options Options
+
+	specIssues   []Issue
+	schemaIssues []Issue
+}
+
+// NewContext indexes both files, expands references and builds the key
+// specs the rules check against. The spec and schema issues are computed
+// once here and filtered by the rules that report them.
+func NewContext(exampleFile, envFile env.File, opts Options) *Context {
+	ctx := &Context{
+		ExampleFile: exampleFile,
+		EnvFile:     envFile,
+		Example:     indexEntries(exampleFile.Entries, opts),
+	}
+
+	// Validate values the way the app sees them, with references expanded
+	actual := indexEntries(envFile.Entries, opts)
+	ctx.Resolution = env.Resolve(actual, opts.Lookup)
+	ctx.Actual = expandEntries(actual, ctx.Resolution)
+
+	specs, issues := buildSpecs(exampleFile)
+	specs, conflicts := mergeSpecs(exampleFile, specs, opts.Keys)
+	ctx.Specs = specs
+	ctx.specIssues = append(issues, conflicts...)
+	ctx.Options = requiredBySpec(specs, opts)
+	ctx.schemaIssues = checkSchema(ctx.Actual, ctx.Specs, ctx.Options)
+	return ctx
+}
+
+// byName returns the entries checked by the name-based rules: those whose
+// keys have no explicit spec.
+func (ctx *Context) byName() map[string]env.Entry {
+	return withoutExplicitSpecs(ctx.Actual, ctx.Specs)
+}
+
+var registry []Rule
+
+// Register adds a rule to the registry that Check runs. It panics if a
+// rule with the same ID is already registered.
+func Register(r Rule) {
+	if _, ok := LookupRule(r.ID()); ok {
+		panic(fmt.Sprintf("lint: rule %s registered twice", r.ID()))
+	}
+	registry = append(registry, r)
+}
+
+// Rules returns the registered rules sorted by ID.
+func Rules() []Rule {
+	rules := slices.Clone(registry)
+	slices.SortFunc(rules, func(a, b Rule) int {
+		return strings.Compare(a.ID(), b.ID())
+	})
+	return rules
+}
+
+// LookupRule returns the registered rule with the given ID.
+func LookupRule(id string) (Rule, bool) {
+	for _, r := range registry {
+		if r.ID() == id {
+			return r, true
+		}
+	}
+	return nil, false
+}
+
+// funcRule adapts a check function to the Rule interface.
+type funcRule struct {
+	id          string
+	severity    Severity
+	description string
+	check       func(ctx *Context) []Issue
+}
+
+func (r funcRule) ID() string                 { return r.id }
+func (r funcRule) DefaultSeverity() Severity  { return r.severity }
+func (r funcRule) Description() string        { return r.description }
+func (r funcRule) Check(ctx *Context) []Issue { return only(r.id, r.check(ctx)) }
+
+// only keeps the issues of rule, for check functions that report several.
+func only(rule string, issues []Issue) []Issue {
+	return slices.DeleteFunc(issues, func(i Issue) bool { return i.Rule != rule })
+}
+
+func init() {
+	bothFiles := func(check func(env.File, Options) []Issue) func(*Context) []Issue {
+		return func(ctx *Context) []Issue {
+			return append(check(ctx.ExampleFile, ctx.Options), check(ctx.EnvFile, ctx.Options)...)
+		}
+	}
+	syntax := bothFiles(func(f env.File, _ Options) []Issue { return checkSyntax(f) })
+	references := func(ctx *Context) []Issue {
+		return checkReferences(ctx.Actual, ctx.Resolution, ctx.Options)
+	}
+	specs := func(ctx *Context) []Issue {
+		return slices.Clone(ctx.specIssues)
+	}
+	// Typed keys report through the rule of the matching name check
+	typed := func(check func(map[string]env.Entry, Options) []Issue) func(*Context) []Issue {
+		return func(ctx *Context) []Issue {
+			return append(check(ctx.byName(), ctx.Options), ctx.schemaIssues...)
+		}
+	}
+	schema := func(ctx *Context) []Issue {
+		return slices.Clone(ctx.schemaIssues)
+	}
+
+	for _, r := range []funcRule{
+		{"syntax-error", SeverityError, "Line without '=', or text after a closing quote", syntax},
+		{"unterminated-quote", SeverityError, "Quoted value never closed", syntax},
+		{"invalid-key-name", SeverityWarning, "Key is not a valid variable name", syntax},
+		{"duplicate-key", SeverityWarning, "Key defined more than once in a file", bothFiles(checkDuplicateKeys)},
+		{"export-prefix", SeverityWarning, "Export prefix does not match the exportPrefix setting", bothFiles(checkExportPrefix)},
+		{"undefined-reference", SeverityWarning, "Reference to an undefined variable", references},
+		{"circular-reference", SeverityError, "References form a cycle", references},
+		{"reference-error", SeverityError, "${VAR:?message} with VAR unset", references},
+		{"missing-key", SeverityError, "Key from the example missing in the env file", func(ctx *Context) []Issue {
+			return checkMissingKeys(ctx.Example, ctx.Actual, ctx.Options)
+		}},
+		{"extra-key", SeverityWarning, "Key in the env file but not in the example", func(ctx *Context) []Issue {
+			extras := checkExtraKeys(ctx.Example, ctx.Actual, ctx.Options)
+			if ctx.Options.NoExtra {
+				for i := range extras {
+					extras[i].Severity = SeverityError
+				}
+			}
+			return extras
+		}},
+		{"required-empty", SeverityError, "Required key has an empty value", func(ctx *Context) []Issue {
+			return checkRequiredEmpty(ctx.Example, ctx.Actual, ctx.Options)
+		}},
+		{"invalid-url", SeverityError, "Key contains URL or has type url, value is not a URL", typed(checkURLFormat)},
+		{"invalid-port", SeverityError, "Key contains PORT or has type port, value not 1-65535", typed(checkPortFormat)},
+		{"invalid-email", SeverityWarning, "Key contains EMAIL or has type email, invalid format", typed(checkEmailFormat)},
+		{"invalid-boolean", SeverityWarning, "Key looks boolean or has type bool, value is not a bool", typed(checkBooleanFormat)},
+		{"surrounding-whitespace", SeverityWarning, "Quoted value starts or ends with whitespace", bothFiles(checkSurroundingWhitespace)},
+		{"invalid-type", SeverityError, "Value does not match its type", schema},
+		{"invalid-enum", SeverityError, "Value is not one of its enum values", schema},
+		{"pattern-mismatch", SeverityError, "Value does not match its pattern", schema},
+		{"out-of-range", SeverityError, "Value outside its min/max or length bounds", schema},
+		{"invalid-annotation", SeverityWarning, "Annotation in the example cannot be used", specs},
+		{"schema-conflict", SeverityWarning, "Config key schema overrides a different annotation", specs},
+		{"deprecated-key", SeverityWarning, "Env file sets a deprecated key", func(ctx *Context) []Issue {
+			return checkDeprecatedKeys(ctx.Actual, ctx.Specs, ctx.Options)
+		}},
+		{"secret-in-example", SeverityError, "Example value looks like a real secret", func(ctx *Context) []Issue {
+			return checkSecrets(ctx.ExampleFile, ctx.Options)
+		}},
+		{"placeholder-value", SeverityError, "Value is a placeholder such as changeme or <your-key>", func(ctx *Context) []Issue {
+			return checkPlaceholders(ctx.Actual, ctx.Options)
+		}},
+		{"unchanged-from-example", SeverityWarning, "Required or secret key still has the example value", func(ctx *Context) []Issue {
+			return checkUnchangedFromExample(ctx.Example, ctx.Actual, ctx.Specs, ctx.Options)
+		}},
+		// Reported by CheckFiles once suppressions are applied
+		{"unused-suppression", SeverityWarning, "envlint-disable directive that suppresses nothing", func(*Context) []Issue {
+			return nil
+		}},
+	} {
+		Register(r)
+	}
+}
@@ -0,0 +1,48 @@
+package lint
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/rasalas/envlint/internal/env"
+)
+
+func TestRulesAreSortedAndUnique(t *testing.T) {
+	rules := Rules()
+	if len(rules) == 0 {
+		t.Fatal("expected registered rules")
+	}
+	ids := make([]string, len(rules))
+	for i, r := range rules {
+		ids[i] = r.ID()
+		if r.Description() == "" {
+			t.Errorf("%s has no description", r.ID())
+		}
+	}
+	if !slices.IsSorted(ids) {
+		t.Errorf("rules not sorted: %v", ids)
+	}
+	if len(slices.Compact(slices.Clone(ids))) != len(ids) {
+		t.Errorf("duplicate rule IDs: %v", ids)
+	}
+}
+
+func TestIssuesMatchRegisteredRules(t *testing.T) {
+	example := env.ParseString("API_URL=\nPORT=80\nNAME=x # @type=int\nMODE= # @enum=a,b\nLEVEL= # @type=uuid\n")
+	envFile := env.ParseString("API_URL=nope\nPORT=99999\nNAME=x\nMODE=c\nLEVEL=1\nNAME=y\nEXTRA=$MISSING\nbad line\n")
+
+	result := CheckFiles(example, envFile, Options{StrictURLs: true, StrictPorts: true})
+	if len(result.Issues) == 0 {
+		t.Fatal("expected issues")
+	}
+	for _, issue := range result.Issues {
+		rule, ok := LookupRule(issue.Rule)
+		if !ok {
+			t.Errorf("issue from unregistered rule %s", issue.Rule)
+			continue
+		}
+		if issue.Rule != "extra-key" && issue.Severity != rule.DefaultSeverity() {
+			t.Errorf("%s: severity %s, default is %s", issue.Rule, issue.Severity, rule.DefaultSeverity())
+		}
+	}
+}
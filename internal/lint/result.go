@@ -1,26 +1,94 @@
 package lint
 
+import (
+	"cmp"
+	"slices"
+)
+
 // Severity represents the severity of a lint issue.
 type Severity string
 
 const (
 	SeverityError   Severity = "error"
 	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+
+	// SeverityOff disables a rule in Options.Severity; issues never carry it.
+	SeverityOff Severity = "off"
 )
 
+// ParseSeverity parses a configured severity: error, warning, info or off.
+func ParseSeverity(s string) (Severity, bool) {
+	switch sev := Severity(s); sev {
+	case SeverityError, SeverityWarning, SeverityInfo, SeverityOff:
+		return sev, true
+	}
+	return "", false
+}
+
 // Issue represents a single lint finding.
 type Issue struct {
 	Rule     string   `json:"rule"`
 	Key      string   `json:"key"`
 	Severity Severity `json:"severity"`
 	Detail   string   `json:"detail,omitempty"`
+	File     string   `json:"file,omitempty"`
 	LineNum  int      `json:"line,omitempty"`
+	Column   int      `json:"column,omitempty"`
+
+	// ExampleLine is the line of the key in the example file. It orders
+	// issues such as missing-key that have no line in the env file and is
+	// left out of the JSON output.
+	ExampleLine int `json:"-"`
+
+	// Fix says whether envlint fix can repair the issue.
+	Fix FixKind `json:"fix,omitempty"`
+}
+
+// FixKind classifies the automatic fix of an issue.
+type FixKind string
+
+const (
+	FixNone   FixKind = ""
+	FixSafe   FixKind = "safe"   // keeps every value the app sees
+	FixUnsafe FixKind = "unsafe" // changes values; needs --unsafe
+)
+
+// sortLine returns the line used to order the issue.
+func (i Issue) sortLine() int {
+	if i.LineNum > 0 {
+		return i.LineNum
+	}
+	return i.ExampleLine
+}
+
+// compareIssues orders issues by file, line, rule and key.
+func compareIssues(a, b Issue) int {
+	return cmp.Or(
+		cmp.Compare(a.File, b.File),
+		cmp.Compare(a.sortLine(), b.sortLine()),
+		cmp.Compare(a.Rule, b.Rule),
+		cmp.Compare(a.Key, b.Key),
+	)
 }
 
 // Result holds all lint findings.
 type Result struct {
-	Issues    []Issue `json:"issues"`
+	Issues []Issue `json:"issues"`
+	// Suppressed holds the issues silenced by envlint-disable directives.
+	Suppressed []Issue `json:"suppressed,omitempty"`
+
+	// Baselined holds the issues known to the baseline, and Fixed the
+	// baseline entries that no longer occur.
+	Baselined []Issue         `json:"baselined,omitempty"`
+	Fixed     []BaselineEntry `json:"fixed,omitempty"`
+
 	totalKeys int
+
+	// usedExample holds the example directives that suppressed an issue.
+	// The example is checked once per env file, so Merge drops its
+	// unused-suppression issues when any file used the directive.
+	usedExample []directiveAt
 }
 
 // AddIssue appends an issue to the result.
@@ -32,6 +100,59 @@ func (r *Result) addAll(issues []Issue) {
 	r.Issues = append(r.Issues, issues...)
 }
 
+// SetFile records path as the file of every issue that has none yet.
+func (r *Result) SetFile(path string) {
+	for i := range r.Issues {
+		if r.Issues[i].File == "" {
+			r.Issues[i].File = path
+		}
+	}
+	r.Sort()
+}
+
+// Merge appends the issues and key count of other to the result. Issues
+// already present, such as example file problems seen once per env file,
+// are not added twice, and an example directive is only reported unused
+// if it suppressed nothing in any file.
+func (r *Result) Merge(other Result) {
+	for _, issue := range other.Issues {
+		if !slices.Contains(r.Issues, issue) {
+			r.Issues = append(r.Issues, issue)
+		}
+	}
+	for _, issue := range other.Suppressed {
+		if !slices.Contains(r.Suppressed, issue) {
+			r.Suppressed = append(r.Suppressed, issue)
+		}
+	}
+	for _, issue := range other.Baselined {
+		if !slices.Contains(r.Baselined, issue) {
+			r.Baselined = append(r.Baselined, issue)
+		}
+	}
+	for _, e := range other.Fixed {
+		if !slices.Contains(r.Fixed, e) {
+			r.Fixed = append(r.Fixed, e)
+		}
+	}
+	slices.SortFunc(r.Fixed, compareBaselineEntries)
+	for _, d := range other.usedExample {
+		if !slices.Contains(r.usedExample, d) {
+			r.usedExample = append(r.usedExample, d)
+		}
+	}
+	r.Issues = slices.DeleteFunc(r.Issues, r.usedElsewhere)
+	r.Baselined = slices.DeleteFunc(r.Baselined, r.usedElsewhere)
+	r.totalKeys += other.totalKeys
+	r.Sort()
+}
+
+// Sort orders issues by file, line, rule and key so output is stable.
+func (r *Result) Sort() {
+	slices.SortStableFunc(r.Issues, compareIssues)
+	slices.SortStableFunc(r.Suppressed, compareIssues)
+}
+
 // SetTotalKeys sets the total number of keys checked.
 func (r *Result) SetTotalKeys(n int) {
 	r.totalKeys = n
@@ -64,6 +185,17 @@ func (r Result) WarnCount() int {
 	return n
 }
 
+// InfoCount returns the number of info-level issues.
+func (r Result) InfoCount() int {
+	n := 0
+	for _, issue := range r.Issues {
+		if issue.Severity == SeverityInfo {
+			n++
+		}
+	}
+	return n
+}
+
 // HasErrors returns true if there are any error-level issues.
 func (r Result) HasErrors() bool {
 	return r.ErrorCount() > 0
@@ -80,11 +212,48 @@ func (r Result) ByRule(rule string) []Issue {
 	return out
 }
 
-// ValueIssues returns all issues that are not missing-key or extra-key.
+// ForFile returns a copy of the result holding only the issues of path.
+func (r Result) ForFile(path string) Result {
+	out := Result{totalKeys: r.totalKeys}
+	for _, issue := range r.Issues {
+		if issue.File == path {
+			out.Issues = append(out.Issues, issue)
+		}
+	}
+	for _, issue := range r.Suppressed {
+		if issue.File == path {
+			out.Suppressed = append(out.Suppressed, issue)
+		}
+	}
+	for _, issue := range r.Baselined {
+		if issue.File == path {
+			out.Baselined = append(out.Baselined, issue)
+		}
+	}
+	for _, e := range r.Fixed {
+		if e.File == baselinePath(path) {
+			out.Fixed = append(out.Fixed, e)
+		}
+	}
+	return out
+}
+
+// SyntaxIssues returns all issues reported by the parser.
+func (r Result) SyntaxIssues() []Issue {
+	var out []Issue
+	for _, issue := range r.Issues {
+		if isSyntaxRule(issue.Rule) {
+			out = append(out, issue)
+		}
+	}
+	return out
+}
+
+// ValueIssues returns all issues that are not missing-key, extra-key or syntax issues.
 func (r Result) ValueIssues() []Issue {
 	var out []Issue
 	for _, issue := range r.Issues {
-		if issue.Rule != "missing-key" && issue.Rule != "extra-key" {
+		if issue.Rule != "missing-key" && issue.Rule != "extra-key" && !isSyntaxRule(issue.Rule) {
 			out = append(out, issue)
 		}
 	}
@@ -102,11 +271,22 @@ func (r *Result) PromoteWarnings() {
 
 // JSONOutput is the JSON-serializable output format.
 type JSONOutput struct {
-	Valid    bool    `json:"valid"`
-	Total   int     `json:"total"`
-	Errors  int     `json:"errors"`
-	Warns   int     `json:"warnings"`
-	Issues  []Issue `json:"issues"`
+	Valid  bool    `json:"valid"`
+	Total  int     `json:"total"`
+	Errors int     `json:"errors"`
+	Warns  int     `json:"warnings"`
+	Infos  int     `json:"infos"`
+	Issues []Issue `json:"issues"`
+
+	// Suppressed counts the issues silenced by directives, which are
+	// listed in SuppressedIssues with --show-suppressed.
+	Suppressed       int     `json:"suppressed"`
+	SuppressedIssues []Issue `json:"suppressedIssues,omitempty"`
+
+	// Baselined counts the issues known to the baseline; Fixed lists the
+	// baseline entries that no longer occur.
+	Baselined int             `json:"baselined,omitempty"`
+	Fixed     []BaselineEntry `json:"fixed,omitempty"`
 }
 
 // ToJSON converts the result to a JSON-friendly struct.
@@ -120,6 +300,11 @@ func (r Result) ToJSON() JSONOutput {
 		Total:  r.totalKeys,
 		Errors: r.ErrorCount(),
 		Warns:  r.WarnCount(),
+		Infos:  r.InfoCount(),
 		Issues: issues,
+
+		Suppressed: len(r.Suppressed),
+		Baselined:  len(r.Baselined),
+		Fixed:      r.Fixed,
 	}
 }
@@ -1,14 +1,177 @@
 package lint
 
 import (
-	"net/url"
+	"fmt"
+	"regexp"
 	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/rasalas/envlint/internal/env"
+	"github.com/rasalas/envlint/internal/secret"
 )
 
+// syntaxRules maps parser diagnostics to rule names and severities.
+var syntaxRules = map[env.DiagnosticKind]struct {
+	rule     string
+	severity Severity
+}{
+	env.DiagMalformedLine:     {"syntax-error", SeverityError},
+	env.DiagTrailingGarbage:   {"syntax-error", SeverityError},
+	env.DiagUnterminatedQuote: {"unterminated-quote", SeverityError},
+	env.DiagInvalidKey:        {"invalid-key-name", SeverityWarning},
+}
+
+// checkSyntax reports the parser diagnostics of a file.
+func checkSyntax(file env.File) []Issue {
+	var issues []Issue
+	for _, d := range file.Diagnostics {
+		r, ok := syntaxRules[d.Kind]
+		if !ok {
+			continue
+		}
+		issues = append(issues, Issue{
+			Rule:     r.rule,
+			Key:      d.Key,
+			Severity: r.severity,
+			Detail:   d.Message,
+			File:     file.Path,
+			LineNum:  d.Line,
+			Column:   d.Column,
+		})
+	}
+	return issues
+}
+
+func isSyntaxRule(rule string) bool {
+	for _, r := range syntaxRules {
+		if r.rule == rule {
+			return true
+		}
+	}
+	return false
+}
+
+// checkDuplicateKeys reports every line of a key that is defined more than
+// once, naming the line whose value the loader ends up using.
+func checkDuplicateKeys(file env.File, opts Options) []Issue {
+	lines := make(map[string][]int)
+	for _, e := range file.Entries {
+		lines[e.Key] = append(lines[e.Key], e.LineNum)
+	}
+
+	var issues []Issue
+	for key, nums := range lines {
+		if len(nums) < 2 || isIgnored(key, opts) {
+			continue
+		}
+		winner, policy := nums[len(nums)-1], "last wins"
+		if opts.FirstWins {
+			winner, policy = nums[0], "first wins"
+		}
+		list := make([]string, len(nums))
+		for i, n := range nums {
+			list[i] = strconv.Itoa(n)
+		}
+		detail := fmt.Sprintf("defined %d times (lines %s), value from line %d is used (%s)",
+			len(nums), strings.Join(list, ", "), winner, policy)
+		for _, n := range nums {
+			issues = append(issues, Issue{
+				Rule:     "duplicate-key",
+				Key:      key,
+				Severity: SeverityWarning,
+				Detail:   detail,
+				File:     file.Path,
+				LineNum:  n,
+				Fix:      FixSafe,
+			})
+		}
+	}
+	return issues
+}
+
+// missingExportDetail is the export-prefix detail of entries that need the
+// prefix added.
+const missingExportDetail = `missing "export" prefix`
+
+// checkExportPrefix reports entries whose "export" prefix does not follow
+// the configured style. In consistent mode the first entry sets the style.
+func checkExportPrefix(file env.File, opts Options) []Issue {
+	if opts.ExportPrefix == "" || len(file.Entries) == 0 {
+		return nil
+	}
+
+	var want bool
+	switch opts.ExportPrefix {
+	case "require":
+		want = true
+	case "forbid":
+		want = false
+	case "consistent":
+		want = file.Entries[0].Export
+	default:
+		return nil
+	}
+
+	detail := missingExportDetail
+	if !want {
+		detail = "unexpected \"export\" prefix"
+	}
+
+	var issues []Issue
+	for _, e := range file.Entries {
+		if e.Export == want || isIgnored(e.Key, opts) {
+			continue
+		}
+		issues = append(issues, Issue{
+			Rule:     "export-prefix",
+			Key:      e.Key,
+			Severity: SeverityWarning,
+			Detail:   detail,
+			File:     file.Path,
+			LineNum:  e.LineNum,
+			Fix:      FixSafe,
+		})
+	}
+	return issues
+}
+
+// checkReferences reports references that cannot be expanded.
+func checkReferences(actual map[string]env.Entry, res env.Resolution, opts Options) []Issue {
+	var issues []Issue
+	for _, p := range res.Problems {
+		if isIgnored(p.Key, opts) {
+			continue
+		}
+		issue := Issue{
+			Key:     p.Key,
+			LineNum: actual[p.Key].LineNum,
+		}
+		switch p.Kind {
+		case env.RefUndefined:
+			issue.Rule = "undefined-reference"
+			issue.Severity = SeverityWarning
+			issue.Detail = "references undefined variable " + p.Name
+		case env.RefCircular:
+			issue.Rule = "circular-reference"
+			issue.Severity = SeverityError
+			issue.Detail = "circular reference: " + strings.Join(p.Path, " → ")
+		case env.RefRequired:
+			msg := p.Message
+			if msg == "" {
+				msg = "required but not set"
+			}
+			issue.Rule = "reference-error"
+			issue.Severity = SeverityError
+			issue.Detail = p.Name + ": " + msg
+		default:
+			continue
+		}
+		issues = append(issues, issue)
+	}
+	return issues
+}
+
 // checkMissingKeys reports keys in example that are missing from env.
 func checkMissingKeys(example, actual map[string]env.Entry, opts Options) []Issue {
 	var issues []Issue
@@ -22,10 +185,12 @@ func checkMissingKeys(example, actual map[string]env.Entry, opts Options) []Issu
 				detail = "required"
 			}
 			issues = append(issues, Issue{
-				Rule:     "missing-key",
-				Key:      key,
-				Severity: SeverityError,
-				Detail:   detail,
+				Rule:        "missing-key",
+				Key:         key,
+				Severity:    SeverityError,
+				Detail:      detail,
+				ExampleLine: ex.LineNum,
+				Fix:         FixSafe,
 			})
 		}
 	}
@@ -44,6 +209,8 @@ func checkExtraKeys(example, actual map[string]env.Entry, opts Options) []Issue
 				Rule:     "extra-key",
 				Key:      key,
 				Severity: SeverityWarning,
+				LineNum:  actual[key].LineNum,
+				Fix:      FixUnsafe,
 			})
 		}
 	}
@@ -61,7 +228,7 @@ func checkRequiredEmpty(example, actual map[string]env.Entry, opts Options) []Is
 		if !ok {
 			continue // handled by checkMissingKeys
 		}
-		// Skip if value contains a variable reference (treated as non-empty)
+		// Skip unresolved references; their value is unknown
 		if act.IsRef {
 			continue
 		}
@@ -79,6 +246,85 @@ func checkRequiredEmpty(example, actual map[string]env.Entry, opts Options) []Is
 	return issues
 }
 
+// placeholderPatterns match values that stand in for a real one. They are
+// matched case-insensitively against the whole trimmed value.
+var placeholderPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)^(change|replace)[_-]?me$`),
+	regexp.MustCompile(`(?i)^(todo|tbd|fixme|placeholder|dummy|secret|password)$`),
+	regexp.MustCompile(`(?i)^x{3,}$`),
+	regexp.MustCompile(`^\.{3,}$`),
+	regexp.MustCompile(`^<[^<>]*>$`),
+	regexp.MustCompile(`(?i)^your[_-].*[_-]here$`),
+	regexp.MustCompile(`(?i)^(your|my)[_-]?(api[_-]?)?(key|secret|token|password)$`),
+}
+
+// isPlaceholder reports whether val matches a built-in or configured
+// placeholder pattern.
+func isPlaceholder(val string, opts Options) bool {
+	val = strings.TrimSpace(val)
+	if val == "" {
+		return false
+	}
+	for _, re := range slices.Concat(placeholderPatterns, opts.Placeholders) {
+		if re.MatchString(val) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkPlaceholders reports values such as "changeme" or "<your-key>"
+// that were never replaced with a real value.
+func checkPlaceholders(actual map[string]env.Entry, opts Options) []Issue {
+	var issues []Issue
+	for key, entry := range actual {
+		if isIgnored(key, opts) || entry.IsRef {
+			continue
+		}
+		if isPlaceholder(entry.Value, opts) {
+			issues = append(issues, Issue{
+				Rule:     "placeholder-value",
+				Key:      key,
+				Severity: SeverityError,
+				Detail:   fmt.Sprintf("placeholder value %q", strings.TrimSpace(entry.Value)),
+				LineNum:  entry.LineNum,
+			})
+		}
+	}
+	return issues
+}
+
+// checkUnchangedFromExample reports required and secret keys whose value
+// was copied from the example. Keys that are only required because of a
+// non-empty example value are skipped: that value is a usable default.
+func checkUnchangedFromExample(example, actual map[string]env.Entry, specs map[string]KeySpec, opts Options) []Issue {
+	var issues []Issue
+	for key, ex := range example {
+		if isIgnored(key, opts) || ex.Value == "" {
+			continue
+		}
+		act, ok := actual[key]
+		if !ok || act.IsRef || act.Value != ex.Value || isPlaceholder(act.Value, opts) {
+			continue
+		}
+		marked := "required"
+		switch {
+		case specs[key].Secret:
+			marked = "secret"
+		case !ex.Required && !isExplicitlyRequired(key, opts):
+			continue
+		}
+		issues = append(issues, Issue{
+			Rule:     "unchanged-from-example",
+			Key:      key,
+			Severity: SeverityWarning,
+			Detail:   marked + " key still has the example value",
+			LineNum:  act.LineNum,
+		})
+	}
+	return issues
+}
+
 // checkURLFormat validates keys containing "URL" have valid URL values.
 func checkURLFormat(actual map[string]env.Entry, opts Options) []Issue {
 	if !opts.StrictURLs {
@@ -96,8 +342,7 @@ func checkURLFormat(actual map[string]env.Entry, opts Options) []Issue {
 		if val == "" || entry.IsRef {
 			continue
 		}
-		u, err := url.Parse(val)
-		if err != nil || u.Scheme == "" || u.Host == "" {
+		if !isURL(val) {
 			issues = append(issues, Issue{
 				Rule:     "invalid-url",
 				Key:      key,
@@ -127,8 +372,7 @@ func checkPortFormat(actual map[string]env.Entry, opts Options) []Issue {
 		if val == "" || entry.IsRef {
 			continue
 		}
-		port, err := strconv.Atoi(val)
-		if err != nil || port < 1 || port > 65535 {
+		if !isPort(val) {
 			issues = append(issues, Issue{
 				Rule:     "invalid-port",
 				Key:      key,
@@ -155,7 +399,7 @@ func checkEmailFormat(actual map[string]env.Entry, opts Options) []Issue {
 		if val == "" || entry.IsRef {
 			continue
 		}
-		if !strings.Contains(val, "@") || !strings.Contains(val, ".") {
+		if !isEmail(val) {
 			issues = append(issues, Issue{
 				Rule:     "invalid-email",
 				Key:      key,
@@ -171,12 +415,6 @@ func checkEmailFormat(actual map[string]env.Entry, opts Options) []Issue {
 // checkBooleanFormat validates keys with boolean-like names have boolean values.
 func checkBooleanFormat(actual map[string]env.Entry, opts Options) []Issue {
 	var issues []Issue
-	boolValues := map[string]bool{
-		"true": true, "false": true,
-		"1": true, "0": true,
-		"yes": true, "no": true,
-		"on": true, "off": true,
-	}
 	for key, entry := range actual {
 		if isIgnored(key, opts) {
 			continue
@@ -188,19 +426,83 @@ func checkBooleanFormat(actual map[string]env.Entry, opts Options) []Issue {
 		if val == "" || entry.IsRef {
 			continue
 		}
-		if !boolValues[strings.ToLower(val)] {
+		if !isBoolean(val) {
 			issues = append(issues, Issue{
 				Rule:     "invalid-boolean",
 				Key:      key,
 				Severity: SeverityWarning,
 				Detail:   "expected boolean value",
 				LineNum:  entry.LineNum,
+				Fix:      booleanFix(val),
+			})
+		}
+	}
+	return issues
+}
+
+// checkSurroundingWhitespace reports quoted values that start or end with
+// spaces or tabs, which are kept by the loader and rarely intended.
+func checkSurroundingWhitespace(file env.File, opts Options) []Issue {
+	var issues []Issue
+	for _, e := range file.Entries {
+		if e.Quote == 0 || isIgnored(e.Key, opts) {
+			continue
+		}
+		if trimmed := strings.Trim(e.Value, " \t"); trimmed != e.Value && trimmed != "" {
+			issues = append(issues, Issue{
+				Rule:     "surrounding-whitespace",
+				Key:      e.Key,
+				Severity: SeverityWarning,
+				Detail:   "value has leading or trailing whitespace",
+				File:     file.Path,
+				LineNum:  e.LineNum,
+				Fix:      FixUnsafe,
 			})
 		}
 	}
 	return issues
 }
 
+// checkSecrets reports example values that look like real credentials.
+// The example is tracked, so a live key committed there is leaked.
+func checkSecrets(exampleFile env.File, opts Options) []Issue {
+	var issues []Issue
+	for _, e := range exampleFile.Entries {
+		if isIgnored(e.Key, opts) || slices.Contains(opts.SecretKeys, e.Key) {
+			continue
+		}
+		if slices.ContainsFunc(opts.SecretAllow, func(re *regexp.Regexp) bool { return re.MatchString(e.Value) }) {
+			continue
+		}
+		if kind, ok := secret.Scan(e.Value); ok {
+			issues = append(issues, Issue{
+				Rule:     "secret-in-example",
+				Key:      e.Key,
+				Severity: SeverityError,
+				Detail:   "possible secret: " + kind,
+				File:     exampleFile.Path,
+				LineNum:  e.LineNum,
+			})
+		}
+	}
+	return issues
+}
+
+// booleanWords maps common spellings that are not accepted as booleans to
+// the value they mean.
+var booleanWords = map[string]string{
+	"y": "true", "t": "true", "enable": "true", "enabled": "true",
+	"n": "false", "f": "false", "disable": "false", "disabled": "false",
+}
+
+// booleanFix returns FixUnsafe if val can be normalized to a boolean.
+func booleanFix(val string) FixKind {
+	if _, ok := booleanWords[strings.ToLower(val)]; ok {
+		return FixUnsafe
+	}
+	return FixNone
+}
+
 // isBooleanKey checks if a key name suggests a boolean value.
 func isBooleanKey(key string) bool {
 	upper := strings.ToUpper(key)
@@ -0,0 +1,486 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rasalas/envlint/internal/env"
+)
+
+// KeySpec describes the expected value of a key.
+type KeySpec struct {
+	Type      string // one of the valueTypes names; empty means any
+	Enum      []string
+	Pattern   string
+	Min, Max  *float64 // numeric bounds for int, float and port values
+	MinLength *int
+	MaxLength *int
+	Required  bool
+	Secret    bool // the env file must not keep the example value
+
+	// Deprecated keys are reported when the env file still sets them.
+	Deprecated  bool
+	Description string
+}
+
+// Validate reports a spec that cannot be applied, such as an unknown type
+// or a pattern that does not compile.
+func (s KeySpec) Validate() error {
+	if _, ok := valueTypes[s.Type]; s.Type != "" && !ok {
+		return fmt.Errorf("unknown type %q", s.Type)
+	}
+	if _, err := regexp.Compile(s.Pattern); err != nil {
+		return fmt.Errorf("invalid pattern %q", s.Pattern)
+	}
+	if s.Min != nil && s.Max != nil && *s.Min > *s.Max {
+		return fmt.Errorf("min %s is greater than max %s", formatNumber(*s.Min), formatNumber(*s.Max))
+	}
+	if (s.MinLength != nil && *s.MinLength < 0) || (s.MaxLength != nil && *s.MaxLength < 0) {
+		return fmt.Errorf("minLength and maxLength must not be negative")
+	}
+	return nil
+}
+
+// explicit reports whether the spec constrains the value itself, which
+// turns off the name-based URL, port, email and boolean checks for the key.
+func (s KeySpec) explicit() bool {
+	return s.Type != "" || len(s.Enum) > 0 || s.Pattern != ""
+}
+
+// valueTypes maps schema type names to their validators.
+var valueTypes = map[string]func(string) bool{
+	"string":   func(string) bool { return true },
+	"int":      isInt,
+	"float":    isFloat,
+	"bool":     isBoolean,
+	"url":      isURL,
+	"port":     isPort,
+	"email":    isEmail,
+	"duration": isDuration,
+	"json":     isJSON,
+}
+
+// typeRules maps schema types to the rule reporting a mismatch. Other
+// types report invalid-type.
+var typeRules = map[string]struct {
+	rule     string
+	severity Severity
+}{
+	"url":   {"invalid-url", SeverityError},
+	"port":  {"invalid-port", SeverityError},
+	"email": {"invalid-email", SeverityWarning},
+	"bool":  {"invalid-boolean", SeverityWarning},
+}
+
+// specFromAnnotations builds a spec from @name=value annotations and
+// returns a message for every annotation it cannot use.
+func specFromAnnotations(a map[string]string) (KeySpec, []string) {
+	var spec KeySpec
+	var problems []string
+
+	if t, ok := a["type"]; ok {
+		if _, known := valueTypes[t]; known {
+			spec.Type = t
+		} else {
+			problems = append(problems, fmt.Sprintf("unknown @type %q", t))
+		}
+	}
+	if e, ok := a["enum"]; ok {
+		for _, v := range strings.Split(e, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				spec.Enum = append(spec.Enum, v)
+			}
+		}
+	}
+	if p, ok := a["pattern"]; ok {
+		if _, err := regexp.Compile(p); err != nil {
+			problems = append(problems, fmt.Sprintf("invalid @pattern %q", p))
+		} else {
+			spec.Pattern = p
+		}
+	}
+	for _, b := range []struct {
+		name string
+		dst  **float64
+	}{{"min", &spec.Min}, {"max", &spec.Max}} {
+		if v, ok := a[b.name]; ok {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("@%s must be a number, got %q", b.name, v))
+				continue
+			}
+			*b.dst = &f
+		}
+	}
+	for _, b := range []struct {
+		name string
+		dst  **int
+	}{{"minLength", &spec.MinLength}, {"maxLength", &spec.MaxLength}} {
+		if v, ok := a[b.name]; ok {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				problems = append(problems, fmt.Sprintf("@%s must be a non-negative integer, got %q", b.name, v))
+				continue
+			}
+			*b.dst = &n
+		}
+	}
+	_, spec.Required = a["required"]
+	_, spec.Secret = a["secret"]
+	_, spec.Deprecated = a["deprecated"]
+	spec.Description = a["description"]
+
+	return spec, problems
+}
+
+// buildSpecs collects the key specs of the example file and reports
+// annotations that cannot be used.
+func buildSpecs(exampleFile env.File) (map[string]KeySpec, []Issue) {
+	specs := make(map[string]KeySpec)
+	var issues []Issue
+	for _, e := range exampleFile.Entries {
+		if len(e.Annotations) == 0 {
+			continue
+		}
+		spec, problems := specFromAnnotations(e.Annotations)
+		specs[e.Key] = spec
+		for _, p := range problems {
+			issues = append(issues, Issue{
+				Rule:     "invalid-annotation",
+				Key:      e.Key,
+				Severity: SeverityWarning,
+				Detail:   p,
+				File:     exampleFile.Path,
+				LineNum:  e.LineNum,
+			})
+		}
+	}
+	return specs, issues
+}
+
+// mergeSpecs combines the specs annotated in the example with those of the
+// [keys] config table. The config wins field by field; fields set in only
+// one place are kept, and a field set differently in both is reported as a
+// schema-conflict on the example line of the key.
+func mergeSpecs(exampleFile env.File, annotated, configured map[string]KeySpec) (map[string]KeySpec, []Issue) {
+	if len(configured) == 0 {
+		return annotated, nil
+	}
+
+	lines := make(map[string]int)
+	for _, e := range exampleFile.Entries {
+		lines[e.Key] = e.LineNum
+	}
+
+	specs := make(map[string]KeySpec, len(annotated)+len(configured))
+	maps.Copy(specs, annotated)
+	var issues []Issue
+	for key, cfg := range configured {
+		ann, ok := annotated[key]
+		if !ok {
+			specs[key] = cfg
+			continue
+		}
+		merged, conflicts := mergeSpec(ann, cfg)
+		specs[key] = merged
+		if len(conflicts) > 0 {
+			issues = append(issues, Issue{
+				Rule:     "schema-conflict",
+				Key:      key,
+				Severity: SeverityWarning,
+				Detail:   "overridden by .envlint.toml: " + strings.Join(conflicts, ", "),
+				File:     exampleFile.Path,
+				LineNum:  lines[key],
+			})
+		}
+	}
+	return specs, issues
+}
+
+// mergeSpec overlays cfg on ann and returns the annotations that cfg
+// overrides with a different value.
+func mergeSpec(ann, cfg KeySpec) (KeySpec, []string) {
+	merged := ann
+	var conflicts []string
+	conflict := func(name, value string) {
+		conflicts = append(conflicts, "@"+name+"="+value)
+	}
+
+	if cfg.Type != "" {
+		if ann.Type != "" && ann.Type != cfg.Type {
+			conflict("type", ann.Type)
+		}
+		merged.Type = cfg.Type
+	}
+	if len(cfg.Enum) > 0 {
+		if len(ann.Enum) > 0 && !slices.Equal(ann.Enum, cfg.Enum) {
+			conflict("enum", strings.Join(ann.Enum, ","))
+		}
+		merged.Enum = cfg.Enum
+	}
+	if cfg.Pattern != "" {
+		if ann.Pattern != "" && ann.Pattern != cfg.Pattern {
+			conflict("pattern", strconv.Quote(ann.Pattern))
+		}
+		merged.Pattern = cfg.Pattern
+	}
+	for _, b := range []struct {
+		name     string
+		ann, cfg *float64
+		dst      **float64
+	}{{"min", ann.Min, cfg.Min, &merged.Min}, {"max", ann.Max, cfg.Max, &merged.Max}} {
+		if b.cfg == nil {
+			continue
+		}
+		if b.ann != nil && *b.ann != *b.cfg {
+			conflict(b.name, formatNumber(*b.ann))
+		}
+		*b.dst = b.cfg
+	}
+	for _, b := range []struct {
+		name     string
+		ann, cfg *int
+		dst      **int
+	}{{"minLength", ann.MinLength, cfg.MinLength, &merged.MinLength}, {"maxLength", ann.MaxLength, cfg.MaxLength, &merged.MaxLength}} {
+		if b.cfg == nil {
+			continue
+		}
+		if b.ann != nil && *b.ann != *b.cfg {
+			conflict(b.name, strconv.Itoa(*b.ann))
+		}
+		*b.dst = b.cfg
+	}
+	merged.Required = ann.Required || cfg.Required
+	merged.Secret = ann.Secret || cfg.Secret
+	merged.Deprecated = ann.Deprecated || cfg.Deprecated
+	if cfg.Description != "" {
+		merged.Description = cfg.Description
+	}
+	return merged, conflicts
+}
+
+// requiredBySpec returns opts with the keys whose spec requires a value
+// added to RequiredKeys.
+func requiredBySpec(specs map[string]KeySpec, opts Options) Options {
+	var keys []string
+	for key, spec := range specs {
+		if spec.Required && !isExplicitlyRequired(key, opts) {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) > 0 {
+		opts.RequiredKeys = append(slices.Clip(opts.RequiredKeys), keys...)
+	}
+	return opts
+}
+
+// withoutExplicitSpecs returns the entries whose keys have no explicit
+// spec, for the name-based checks.
+func withoutExplicitSpecs(actual map[string]env.Entry, specs map[string]KeySpec) map[string]env.Entry {
+	out := make(map[string]env.Entry, len(actual))
+	for k, e := range actual {
+		if spec, ok := specs[k]; ok && spec.explicit() {
+			continue
+		}
+		out[k] = e
+	}
+	return out
+}
+
+// InferType guesses the schema type of a key from its current value, for
+// writing @type annotations. Only a key named PORT or ending in _PORT
+// makes a number a port. It returns "" for plain strings and empty values.
+func InferType(key, val string) string {
+	val = strings.TrimSpace(val)
+	switch {
+	case val == "":
+		return ""
+	case isBoolean(val) && (!isInt(val) || isBooleanKey(key)):
+		return "bool"
+	case isPortKey(key) && isPort(val):
+		return "port"
+	case isInt(val):
+		return "int"
+	case isFloat(val) && !strings.ContainsAny(val, "eEnN"):
+		return "float"
+	case isDuration(val):
+		return "duration"
+	case strings.ContainsAny(val[:1], "[{") && isJSON(val):
+		return "json"
+	case isURL(val):
+		return "url"
+	case isEmail(val) && !strings.ContainsAny(val, " \t/:"):
+		return "email"
+	}
+	return ""
+}
+
+// isPortKey reports whether key is PORT or ends in _PORT.
+func isPortKey(key string) bool {
+	key = strings.ToUpper(key)
+	return key == "PORT" || strings.HasSuffix(key, "_PORT")
+}
+
+// checkSchema validates values against the spec of their key.
+func checkSchema(actual map[string]env.Entry, specs map[string]KeySpec, opts Options) []Issue {
+	var issues []Issue
+	for key, entry := range actual {
+		spec, ok := specs[key]
+		if !ok || isIgnored(key, opts) {
+			continue
+		}
+		val := strings.TrimSpace(entry.Value)
+		if val == "" || entry.IsRef {
+			continue
+		}
+		issue := func(rule string, severity Severity, detail string) {
+			issues = append(issues, Issue{
+				Rule:     rule,
+				Key:      key,
+				Severity: severity,
+				Detail:   detail,
+				LineNum:  entry.LineNum,
+			})
+		}
+
+		if spec.Type != "" && !valueTypes[spec.Type](val) {
+			if r, ok := typeRules[spec.Type]; ok {
+				issue(r.rule, r.severity, typeDetail(spec.Type, val))
+				if spec.Type == "bool" {
+					issues[len(issues)-1].Fix = booleanFix(val)
+				}
+			} else {
+				issue("invalid-type", SeverityError, "expected "+spec.Type)
+			}
+			continue
+		}
+
+		if len(spec.Enum) > 0 && !slices.Contains(spec.Enum, val) {
+			issue("invalid-enum", SeverityError, "expected one of "+strings.Join(spec.Enum, ", "))
+		}
+		if spec.Pattern != "" {
+			if re, err := regexp.Compile(spec.Pattern); err == nil && !re.MatchString(val) {
+				issue("pattern-mismatch", SeverityError, "does not match pattern "+spec.Pattern)
+			}
+		}
+		if detail := rangeDetail(spec, val); detail != "" {
+			issue("out-of-range", SeverityError, detail)
+		}
+	}
+	return issues
+}
+
+// checkDeprecatedKeys reports deprecated keys that the env file still sets.
+func checkDeprecatedKeys(actual map[string]env.Entry, specs map[string]KeySpec, opts Options) []Issue {
+	var issues []Issue
+	for key, entry := range actual {
+		spec, ok := specs[key]
+		if !ok || !spec.Deprecated || isIgnored(key, opts) {
+			continue
+		}
+		detail := "deprecated"
+		if spec.Description != "" {
+			detail += ": " + spec.Description
+		}
+		issues = append(issues, Issue{
+			Rule:     "deprecated-key",
+			Key:      key,
+			Severity: SeverityWarning,
+			Detail:   detail,
+			LineNum:  entry.LineNum,
+		})
+	}
+	return issues
+}
+
+// rangeDetail returns why val is outside the bounds of spec, or "".
+func rangeDetail(spec KeySpec, val string) string {
+	switch spec.Type {
+	case "int", "float", "port":
+		n, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			break
+		}
+		if spec.Min != nil && n < *spec.Min {
+			return fmt.Sprintf("must be at least %s, got %s", formatNumber(*spec.Min), val)
+		}
+		if spec.Max != nil && n > *spec.Max {
+			return fmt.Sprintf("must be at most %s, got %s", formatNumber(*spec.Max), val)
+		}
+	}
+	n := len([]rune(val))
+	if spec.MinLength != nil && n < *spec.MinLength {
+		return fmt.Sprintf("must be at least %d characters, got %d", *spec.MinLength, n)
+	}
+	if spec.MaxLength != nil && n > *spec.MaxLength {
+		return fmt.Sprintf("must be at most %d characters, got %d", *spec.MaxLength, n)
+	}
+	return ""
+}
+
+// typeDetail matches the details of the name-based checks.
+func typeDetail(typ, val string) string {
+	switch typ {
+	case "url":
+		return "invalid URL format"
+	case "port":
+		return "must be 1-65535, got " + strconv.Quote(val)
+	case "email":
+		return "invalid email format"
+	case "bool":
+		return "expected boolean value"
+	}
+	return "expected " + typ
+}
+
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func isInt(val string) bool {
+	_, err := strconv.ParseInt(val, 10, 64)
+	return err == nil
+}
+
+func isFloat(val string) bool {
+	_, err := strconv.ParseFloat(val, 64)
+	return err == nil
+}
+
+func isBoolean(val string) bool {
+	switch strings.ToLower(val) {
+	case "true", "false", "1", "0", "yes", "no", "on", "off":
+		return true
+	}
+	return false
+}
+
+func isURL(val string) bool {
+	u, err := url.Parse(val)
+	return err == nil && u.Scheme != "" && u.Host != ""
+}
+
+func isPort(val string) bool {
+	port, err := strconv.Atoi(val)
+	return err == nil && port >= 1 && port <= 65535
+}
+
+func isEmail(val string) bool {
+	return strings.Contains(val, "@") && strings.Contains(val, ".")
+}
+
+func isDuration(val string) bool {
+	_, err := time.ParseDuration(val)
+	return err == nil
+}
+
+func isJSON(val string) bool {
+	return json.Valid([]byte(val))
+}
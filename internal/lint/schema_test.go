@@ -0,0 +1,161 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/rasalas/envlint/internal/env"
+)
+
+func TestSpecFromAnnotations(t *testing.T) {
+	spec, problems := specFromAnnotations(map[string]string{
+		"type": "int", "min": "1", "max": "100", "enum": "a, b,c", "pattern": "^sk_", "required": "",
+	})
+	if len(problems) != 0 {
+		t.Fatalf("unexpected problems: %v", problems)
+	}
+	if spec.Type != "int" || *spec.Min != 1 || *spec.Max != 100 || !spec.Required {
+		t.Errorf("unexpected spec: %+v", spec)
+	}
+	if len(spec.Enum) != 3 || spec.Enum[1] != "b" {
+		t.Errorf("unexpected enum: %v", spec.Enum)
+	}
+
+	_, problems = specFromAnnotations(map[string]string{
+		"type": "uuid", "min": "low", "pattern": "([", "maxLength": "-1",
+	})
+	if len(problems) != 4 {
+		t.Errorf("expected 4 problems, got %v", problems)
+	}
+}
+
+func TestCheckSchema(t *testing.T) {
+	one, hundred, three := 1.0, 100.0, 3
+	specs := map[string]KeySpec{
+		"TIMEOUT_MS": {Type: "int", Min: &one, Max: &hundred},
+		"RETRIES":    {Type: "int"},
+		"LOG_LEVEL":  {Enum: []string{"debug", "info", "warn"}},
+		"API_KEY":    {Pattern: "^sk_", MinLength: &three},
+		"CALLBACK":   {Type: "url"},
+		"TTL":        {Type: "duration"},
+	}
+	actual := map[string]env.Entry{
+		"TIMEOUT_MS": {Key: "TIMEOUT_MS", Value: "500"},
+		"RETRIES":    {Key: "RETRIES", Value: "three"},
+		"LOG_LEVEL":  {Key: "LOG_LEVEL", Value: "verbose"},
+		"API_KEY":    {Key: "API_KEY", Value: "pk"},
+		"CALLBACK":   {Key: "CALLBACK", Value: "nope"},
+		"TTL":        {Key: "TTL", Value: "30s"},
+	}
+
+	got := map[string][]string{}
+	for _, issue := range checkSchema(actual, specs, Options{}) {
+		got[issue.Key] = append(got[issue.Key], issue.Rule)
+	}
+
+	want := map[string][]string{
+		"TIMEOUT_MS": {"out-of-range"},
+		"RETRIES":    {"invalid-type"},
+		"LOG_LEVEL":  {"invalid-enum"},
+		"CALLBACK":   {"invalid-url"},
+	}
+	for key, rules := range want {
+		if len(got[key]) != len(rules) || got[key][0] != rules[0] {
+			t.Errorf("%s: expected %v, got %v", key, rules, got[key])
+		}
+	}
+	if len(got["API_KEY"]) != 2 {
+		t.Errorf("API_KEY: expected pattern and length issues, got %v", got["API_KEY"])
+	}
+	if len(got["TTL"]) != 0 {
+		t.Errorf("TTL: expected no issues, got %v", got["TTL"])
+	}
+}
+
+func TestAnnotationsOverrideNameHeuristics(t *testing.T) {
+	example := env.ParseString("LOG_PORTAL=internal # @type=string\nTIMEOUT_MS=1000 # @type=int @max=5000\n")
+	envFile := env.ParseString("LOG_PORTAL=internal\nTIMEOUT_MS=9000\n")
+
+	result := CheckFiles(example, envFile, Options{StrictPorts: true})
+
+	if len(result.ByRule("invalid-port")) != 0 {
+		t.Errorf("LOG_PORTAL is annotated as string and must not be port-checked, got %+v", result.Issues)
+	}
+	ranges := result.ByRule("out-of-range")
+	if len(ranges) != 1 || ranges[0].Key != "TIMEOUT_MS" {
+		t.Errorf("expected TIMEOUT_MS to be out of range, got %+v", result.Issues)
+	}
+}
+
+func TestConfigSpecsTakePrecedence(t *testing.T) {
+	example := env.ParseString("TIMEOUT_MS=1000 # @type=int @max=5000\nMODE=fast\nLEGACY_TOKEN=\n")
+	example.Path = ".env.example"
+	envFile := env.ParseString("TIMEOUT_MS=9000\nMODE=slow\nLEGACY_TOKEN=abc\n")
+
+	max := 10000.0
+	result := CheckFiles(example, envFile, Options{Keys: map[string]KeySpec{
+		"TIMEOUT_MS":   {Max: &max},
+		"MODE":         {Enum: []string{"fast", "safe"}, Required: true},
+		"LEGACY_TOKEN": {Deprecated: true, Description: "use API_KEY"},
+	}})
+
+	if ranges := result.ByRule("out-of-range"); len(ranges) != 0 {
+		t.Errorf("config max should override @max, got %+v", ranges)
+	}
+	conflicts := result.ByRule("schema-conflict")
+	if len(conflicts) != 1 || conflicts[0].Key != "TIMEOUT_MS" || conflicts[0].File != ".env.example" || conflicts[0].LineNum != 1 {
+		t.Fatalf("expected one schema-conflict on TIMEOUT_MS, got %+v", conflicts)
+	}
+	if conflicts[0].Detail != "overridden by .envlint.toml: @max=5000" {
+		t.Errorf("unexpected detail: %s", conflicts[0].Detail)
+	}
+	if enums := result.ByRule("invalid-enum"); len(enums) != 1 || enums[0].Key != "MODE" {
+		t.Errorf("expected MODE enum issue from config, got %+v", enums)
+	}
+	deprecated := result.ByRule("deprecated-key")
+	if len(deprecated) != 1 || deprecated[0].Detail != "deprecated: use API_KEY" {
+		t.Errorf("expected LEGACY_TOKEN deprecation, got %+v", deprecated)
+	}
+}
+
+func TestKeySpecValidate(t *testing.T) {
+	lo, hi, neg := 10.0, 1.0, -1
+	for _, spec := range []KeySpec{
+		{Type: "uuid"},
+		{Pattern: "(["},
+		{Min: &lo, Max: &hi},
+		{MinLength: &neg},
+	} {
+		if spec.Validate() == nil {
+			t.Errorf("expected %+v to be invalid", spec)
+		}
+	}
+	if err := (KeySpec{Type: "int", Min: &hi, Max: &lo}).Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestInferType(t *testing.T) {
+	tests := []struct {
+		key, val, want string
+	}{
+		{"APP_PORT", "3000", "port"},
+		{"PORT", "8080", "port"},
+		{"LOG_PORTAL", "8080", "int"},
+		{"PORTS_OPEN", "3", "int"},
+		{"WORKERS", "4", "int"},
+		{"DEBUG", "1", "bool"},
+		{"CACHE", "true", "bool"},
+		{"RATIO", "0.5", "float"},
+		{"TIMEOUT", "30s", "duration"},
+		{"FEATURES", `{"beta":true}`, "json"},
+		{"API_URL", "https://api.example.com", "url"},
+		{"ADMIN_EMAIL", "ops@example.com", "email"},
+		{"LOG_LEVEL", "info", ""},
+		{"NAME", "", ""},
+	}
+	for _, tt := range tests {
+		if got := InferType(tt.key, tt.val); got != tt.want {
+			t.Errorf("InferType(%s, %q) = %q, want %q", tt.key, tt.val, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package lint
+
+import (
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/rasalas/envlint/internal/env"
+)
+
+// suppression is the range of lines on which a directive disables rules.
+type suppression struct {
+	directive env.Directive
+	file      string
+	example   bool // the directive is in the example file
+	from, to  int  // inclusive line range
+	used      bool
+
+	// enabled maps rules an envlint-enable ended early to its line.
+	enabled map[string]int
+}
+
+// suppressions returns the line ranges disabled by the directives of file.
+// An envlint-disable block runs to the end of the file or to an
+// envlint-enable without rules. An envlint-enable that lists rules ends
+// the block for those rules only.
+func suppressions(file env.File, example bool) []*suppression {
+	var out, open []*suppression
+	for _, d := range file.Directives {
+		s := &suppression{directive: d, file: file.Path, example: example, from: d.Line, to: d.Line}
+		switch d.Kind {
+		case env.DisableLine:
+		case env.DisableNextLine:
+			s.from, s.to = d.Line+1, d.Line+1
+		case env.Disable:
+			s.to = math.MaxInt
+			open = append(open, s)
+		case env.Enable:
+			open = slices.DeleteFunc(open, func(b *suppression) bool { return b.enable(d) })
+			continue
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// enable ends the open block s for the rules of an envlint-enable
+// directive and reports whether the block is now closed for every rule.
+func (s *suppression) enable(d env.Directive) bool {
+	if len(d.Rules) == 0 {
+		s.to = d.Line
+		return true
+	}
+	for _, r := range d.Rules {
+		if len(s.directive.Rules) > 0 && !slices.Contains(s.directive.Rules, r) {
+			continue
+		}
+		if _, ok := s.enabled[r]; !ok {
+			if s.enabled == nil {
+				s.enabled = make(map[string]int)
+			}
+			s.enabled[r] = d.Line
+		}
+	}
+	if len(s.directive.Rules) == 0 || len(s.enabled) < len(s.directive.Rules) {
+		return false
+	}
+	s.to = d.Line
+	return true
+}
+
+// covers reports whether the suppression applies to issue. Issues without
+// a line, such as missing-key, are matched by their example line.
+func (s *suppression) covers(issue Issue) bool {
+	if len(s.directive.Rules) > 0 && !slices.Contains(s.directive.Rules, issue.Rule) {
+		return false
+	}
+	line := issue.LineNum
+	switch {
+	case issue.File == s.file && line > 0:
+	case s.example && line == 0:
+		line = issue.ExampleLine
+	default:
+		return false
+	}
+	if end, ok := s.enabled[issue.Rule]; ok && line > end {
+		return false
+	}
+	return line >= s.from && line <= s.to
+}
+
+// suppress moves the issues covered by a suppression to r.Suppressed.
+func (r *Result) suppress(sups []*suppression) {
+	if len(sups) == 0 {
+		return
+	}
+	kept := r.Issues[:0]
+	for _, issue := range r.Issues {
+		covered := false
+		for _, s := range sups {
+			if s.covers(issue) {
+				s.used = true
+				covered = true
+			}
+		}
+		if covered {
+			r.Suppressed = append(r.Suppressed, issue)
+		} else {
+			kept = append(kept, issue)
+		}
+	}
+	r.Issues = kept
+}
+
+// directiveAt identifies a directive by its file and line.
+type directiveAt struct {
+	file string
+	line int
+}
+
+// usedElsewhere reports whether issue is an unused-suppression of an
+// example directive that suppressed an issue of another env file.
+func (r *Result) usedElsewhere(issue Issue) bool {
+	return issue.Rule == "unused-suppression" && slices.Contains(r.usedExample, directiveAt{issue.File, issue.LineNum})
+}
+
+// checkUnusedSuppressions reports directives that suppressed nothing.
+func checkUnusedSuppressions(sups []*suppression) []Issue {
+	var issues []Issue
+	for _, s := range sups {
+		if s.used {
+			continue
+		}
+		directive := string(s.directive.Kind)
+		if len(s.directive.Rules) > 0 {
+			directive += " " + strings.Join(s.directive.Rules, ", ")
+		}
+		issues = append(issues, Issue{
+			Rule:     "unused-suppression",
+			Severity: SeverityWarning,
+			Detail:   directive + " suppresses nothing",
+			File:     s.file,
+			LineNum:  s.directive.Line,
+		})
+	}
+	return issues
+}
@@ -0,0 +1,111 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/rasalas/envlint/internal/env"
+)
+
+func TestCheckSuppressions(t *testing.T) {
+	example := env.ParseString("API_URL=\nDB_PORT=\nADMIN_EMAIL=\n# envlint-disable-next-line missing-key\nLOCAL_ONLY=\n")
+	example.Path = ".env.example"
+	envFile := env.ParseString(`# envlint-disable-next-line invalid-url
+API_URL=nope
+DB_PORT=abc # envlint-disable-line invalid-url
+# envlint-disable
+ADMIN_EMAIL=nope
+# envlint-enable
+`)
+	envFile.Path = ".env"
+
+	result := CheckFiles(example, envFile, Options{StrictURLs: true, StrictPorts: true})
+
+	suppressed := map[string]bool{}
+	for _, issue := range result.Suppressed {
+		suppressed[issue.Rule] = true
+	}
+	for _, rule := range []string{"invalid-url", "invalid-email", "missing-key"} {
+		if !suppressed[rule] {
+			t.Errorf("expected %s to be suppressed, got %+v", rule, result.Suppressed)
+		}
+		if len(result.ByRule(rule)) != 0 {
+			t.Errorf("%s should not be reported, got %+v", rule, result.ByRule(rule))
+		}
+	}
+	if len(result.ByRule("invalid-port")) != 1 {
+		t.Errorf("DB_PORT directive names another rule, expected invalid-port, got %+v", result.Issues)
+	}
+
+	unused := result.ByRule("unused-suppression")
+	if len(unused) != 1 || unused[0].LineNum != 3 || unused[0].File != ".env" {
+		t.Fatalf("expected one unused suppression on line 3, got %+v", unused)
+	}
+	if unused[0].Detail != "envlint-disable-line invalid-url suppresses nothing" {
+		t.Errorf("unexpected detail: %s", unused[0].Detail)
+	}
+	if got := result.ToJSON().Suppressed; got != 3 {
+		t.Errorf("expected 3 suppressed in JSON, got %d", got)
+	}
+}
+
+func TestSuppressionBlocks(t *testing.T) {
+	file := env.ParseString(`# envlint-disable invalid-url, invalid-port
+A=1
+# envlint-enable invalid-port
+B=2
+# envlint-enable invalid-url
+# envlint-disable
+C=3
+# envlint-enable invalid-url
+D=4
+`)
+	sups := suppressions(file, false)
+	if len(sups) != 2 {
+		t.Fatalf("expected 2 blocks, got %d", len(sups))
+	}
+	first := sups[0]
+	if first.from != 1 || first.to != 5 {
+		t.Errorf("first block should end when its last rule is enabled, got %d-%d", first.from, first.to)
+	}
+	if first.covers(Issue{Rule: "invalid-port", LineNum: 4}) {
+		t.Error("invalid-port should be enabled again after line 3")
+	}
+	if !first.covers(Issue{Rule: "invalid-url", LineNum: 4}) {
+		t.Error("enabling invalid-port should keep invalid-url disabled")
+	}
+	if !sups[1].covers(Issue{Rule: "any", LineNum: 100}) {
+		t.Error("an unclosed block should run to the end of the file")
+	}
+	if sups[1].covers(Issue{Rule: "invalid-url", LineNum: 9}) {
+		t.Error("enabling invalid-url should end a block of all rules for it")
+	}
+}
+
+func TestExampleSuppressionAcrossEnvFiles(t *testing.T) {
+	example := env.ParseString("APP_NAME=\n# envlint-disable-next-line missing-key\nDEBUG=\n")
+	example.Path = ".env.example"
+	base := env.ParseString("APP_NAME=app\n")
+	base.Path = ".env"
+	local := env.ParseString("APP_NAME=app\nDEBUG=true\n")
+	local.Path = ".env.local"
+
+	for _, order := range [][]env.File{{base, local}, {local, base}} {
+		var combined Result
+		for _, f := range order {
+			combined.Merge(CheckFiles(example, f, Options{}))
+		}
+		if unused := combined.ByRule("unused-suppression"); len(unused) != 0 {
+			t.Errorf("directive used by %s should not be reported, got %+v", base.Path, unused)
+		}
+		if len(combined.Suppressed) != 1 {
+			t.Errorf("expected the missing DEBUG to be suppressed, got %+v", combined.Suppressed)
+		}
+	}
+
+	var combined Result
+	combined.Merge(CheckFiles(example, local, Options{}))
+	combined.Merge(CheckFiles(example, local, Options{}))
+	if unused := combined.ByRule("unused-suppression"); len(unused) != 1 || unused[0].File != ".env.example" {
+		t.Errorf("directive no file used should be reported once, got %+v", unused)
+	}
+}
@@ -0,0 +1,70 @@
+package report
+
+import (
+	"encoding/xml"
+	"io"
+
+	"github.com/rasalas/envlint/internal/lint"
+)
+
+type checkstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr,omitempty"`
+	Column   int    `xml:"column,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// Checkstyle writes Checkstyle XML with a file element for every linted
+// file, the example included. Issues without a line have no line
+// attribute.
+type Checkstyle struct{}
+
+func (Checkstyle) Report(w io.Writer, run Run) error {
+	out := checkstyleReport{Version: "4.3"}
+	for _, path := range run.Paths() {
+		file := checkstyleFile{Name: relPath(path, run.Root)}
+		for _, issue := range run.Result.ForFile(path).Issues {
+			file.Errors = append(file.Errors, checkstyleError{
+				Line:     issue.LineNum,
+				Column:   issue.Column,
+				Severity: checkstyleSeverity(issue.Severity),
+				Message:  Message(issue),
+				Source:   "envlint." + issue.Rule,
+			})
+		}
+		out.Files = append(out.Files, file)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(out); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func checkstyleSeverity(sev lint.Severity) string {
+	switch sev {
+	case lint.SeverityError:
+		return "error"
+	case lint.SeverityWarning:
+		return "warning"
+	}
+	return "info"
+}
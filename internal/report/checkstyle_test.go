@@ -0,0 +1,44 @@
+package report
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/rasalas/envlint/internal/lint"
+)
+
+func TestCheckstyle(t *testing.T) {
+	result := lint.Result{Issues: []lint.Issue{
+		{Rule: "invalid-port", Key: "PORT", Severity: lint.SeverityError, Detail: `got "x"`, File: ".env", LineNum: 2},
+		{Rule: "missing-key", Key: "API_KEY", Severity: lint.SeverityError, File: ".env"},
+		{Rule: "invalid-boolean", Key: "CACHE", Severity: lint.SeverityInfo, File: ".env", LineNum: 3},
+	}}
+	run := Run{Result: result, Files: []FileResult{{Path: ".env", Result: result}}, Example: ".env.example"}
+
+	var buf bytes.Buffer
+	if err := (Checkstyle{}).Report(&buf, run); err != nil {
+		t.Fatal(err)
+	}
+	var out checkstyleReport
+	if err := xml.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, buf.String())
+	}
+
+	if len(out.Files) != 2 || out.Files[0].Name != ".env" || out.Files[1].Name != ".env.example" {
+		t.Fatalf("unexpected files: %+v", out.Files)
+	}
+	errs := out.Files[0].Errors
+	if len(errs) != 3 {
+		t.Fatalf("expected 3 errors, got %d", len(errs))
+	}
+	if errs[0].Line != 2 || errs[0].Source != "envlint.invalid-port" || errs[0].Message != `PORT: got "x"` {
+		t.Errorf("unexpected error: %+v", errs[0])
+	}
+	if errs[1].Line != 0 || errs[2].Severity != "info" {
+		t.Errorf("unexpected errors: %+v", errs[1:])
+	}
+	if len(out.Files[1].Errors) != 0 {
+		t.Error("expected no errors for the example")
+	}
+}
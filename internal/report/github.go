@@ -0,0 +1,127 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/rasalas/envlint/internal/lint"
+)
+
+// GitHub writes GitHub Actions workflow commands, which show up as
+// annotations on the changed files of a pull request. Paths are relative
+// to Run.Root, the checkout directory. Baseline entries that no longer
+// occur are reported as notices. When $GITHUB_STEP_SUMMARY is set, a
+// job summary is appended to the file it names.
+type GitHub struct{}
+
+func (GitHub) Report(w io.Writer, run Run) error {
+	if err := githubCommands(w, run.Result, run.Root); err != nil {
+		return err
+	}
+	path := os.Getenv("GITHUB_STEP_SUMMARY")
+	if path == "" {
+		return nil
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("cannot write job summary: %w", err)
+	}
+	if err := githubSummary(f, run); err != nil {
+		f.Close()
+		return fmt.Errorf("cannot write job summary: %w", err)
+	}
+	return f.Close()
+}
+
+func githubCommands(w io.Writer, result lint.Result, root string) error {
+	for _, issue := range result.Issues {
+		props := []string{"file=" + escapeProperty(relPath(issue.File, root))}
+		if issue.LineNum > 0 {
+			props = append(props, "line="+strconv.Itoa(issue.LineNum))
+			if issue.Column > 0 {
+				props = append(props, "col="+strconv.Itoa(issue.Column))
+			}
+		}
+		props = append(props, "title="+escapeProperty(issue.Rule))
+		if _, err := fmt.Fprintf(w, "::%s %s::%s\n", githubCommand(issue.Severity), strings.Join(props, ","), escapeData(Message(issue))); err != nil {
+			return err
+		}
+	}
+	for _, e := range result.Fixed {
+		props := "file=" + escapeProperty(relPath(fixedIssue(e).File, root)) + ",title=" + escapeProperty(e.Rule)
+		if _, err := fmt.Fprintf(w, "::notice %s::%s\n", props, escapeData(fixedMessage(e))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// githubSummary writes a Markdown job summary with a row of counts per
+// file, a table of all issues and the baseline entries that no longer
+// occur.
+func githubSummary(w io.Writer, run Run) error {
+	result, files, root := run.Result, run.Paths(), run.Root
+	var b strings.Builder
+	b.WriteString("## envlint\n\n")
+	errors, warnings, infos := result.ErrorCount(), result.WarnCount(), result.InfoCount()
+	if errors+warnings+infos == 0 {
+		fmt.Fprintf(&b, ":white_check_mark: No issues in %d file(s)\n", len(files))
+	} else {
+		icon := ":warning:"
+		if errors > 0 {
+			icon = ":x:"
+		}
+		fmt.Fprintf(&b, "%s %d error(s), %d warning(s), %d info in %d file(s)\n", icon, errors, warnings, infos, len(files))
+	}
+
+	b.WriteString("\n| File | Errors | Warnings | Info |\n|------|-------:|---------:|-----:|\n")
+	for _, file := range files {
+		r := result.ForFile(file)
+		fmt.Fprintf(&b, "| `%s` | %d | %d | %d |\n", relPath(file, root), r.ErrorCount(), r.WarnCount(), r.InfoCount())
+	}
+
+	if len(result.Issues) > 0 {
+		b.WriteString("\n| Severity | Rule | Location | Message |\n|----------|------|----------|---------|\n")
+		for _, issue := range result.Issues {
+			fmt.Fprintf(&b, "| %s | `%s` | `%s` | %s |\n", issue.Severity, issue.Rule, position(issue, root), escapeCell(Message(issue)))
+		}
+	}
+
+	if len(result.Fixed) > 0 {
+		b.WriteString("\n### Fixed since baseline\n\n")
+		for _, e := range result.Fixed {
+			fmt.Fprintf(&b, "- `%s` %s\n", relPath(fixedIssue(e).File, root), fixedMessage(e))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func githubCommand(sev lint.Severity) string {
+	switch sev {
+	case lint.SeverityError:
+		return "error"
+	case lint.SeverityWarning:
+		return "warning"
+	}
+	return "notice"
+}
+
+// escapeData escapes the message of a workflow command.
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeProperty escapes a workflow command property value.
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+// escapeCell keeps text from breaking a Markdown table row.
+func escapeCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\r", " ", "\n", " ").Replace(s)
+}
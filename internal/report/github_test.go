@@ -0,0 +1,66 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/rasalas/envlint/internal/lint"
+)
+
+func TestGitHub(t *testing.T) {
+	result := lint.Result{Issues: []lint.Issue{
+		{Rule: "missing-key", Key: "API_KEY", Severity: lint.SeverityError, Detail: "required", File: ".env"},
+		{Rule: "syntax-error", Severity: lint.SeverityError, Detail: "50% done,\nthen: stop", File: "a,b:c/.env", LineNum: 4, Column: 2},
+		{Rule: "extra-key", Key: "DEBUG", Severity: lint.SeverityWarning, File: ".env", LineNum: 2},
+		{Rule: "invalid-boolean", Key: "CACHE", Severity: lint.SeverityInfo, Detail: "expected boolean value", File: ".env", LineNum: 3},
+	}}
+	result.Fixed = []lint.BaselineEntry{{Rule: "invalid-url", Key: "API_URL", File: ".env", Count: 1}}
+
+	var buf bytes.Buffer
+	if err := githubCommands(&buf, result, ""); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"::error file=.env,title=missing-key::API_KEY: missing in .env (required)",
+		"::error file=a%2Cb%3Ac/.env,line=4,col=2,title=syntax-error::50%25 done,%0Athen: stop",
+		"::warning file=.env,line=2,title=extra-key::DEBUG: not in the example",
+		"::notice file=.env,line=3,title=invalid-boolean::CACHE: expected boolean value",
+		"::notice file=.env,title=invalid-url::API_URL: invalid-url is fixed since the baseline, run with --write-baseline to update it",
+	}
+	if got := strings.Split(strings.TrimSpace(buf.String()), "\n"); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected commands:\n%s", buf.String())
+	}
+}
+
+func TestGitHubSummary(t *testing.T) {
+	result := lint.Result{Issues: []lint.Issue{
+		{Rule: "invalid-url", Key: "API_URL", Severity: lint.SeverityError, Detail: "a | b", File: ".env", LineNum: 1},
+	}}
+	result.Fixed = []lint.BaselineEntry{{Rule: "missing-key", Key: "DEBUG", File: ".env", Count: 1}}
+
+	var buf bytes.Buffer
+	run := Run{Result: result, Files: []FileResult{{Path: ".env"}}, Example: ".env.example"}
+	if err := githubSummary(&buf, run); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		":x: 1 error(s), 0 warning(s), 0 info in 2 file(s)",
+		"| `.env` | 1 | 0 | 0 |",
+		"| `.env.example` | 0 | 0 | 0 |",
+		"| error | `invalid-url` | `.env:1` | API_URL: a \\| b |",
+		"### Fixed since baseline",
+		"- `.env` DEBUG: missing-key is fixed since the baseline",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("summary is missing %q:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	githubSummary(&buf, Run{Files: []FileResult{{Path: ".env"}}, Example: ".env.example"})
+	if !strings.Contains(buf.String(), "No issues in 2 file(s)") || strings.Contains(buf.String(), "| Severity |") {
+		t.Errorf("unexpected summary for a clean run:\n%s", buf.String())
+	}
+}
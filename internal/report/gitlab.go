@@ -0,0 +1,58 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/rasalas/envlint/internal/lint"
+)
+
+type gitlabIssue struct {
+	Description string         `json:"description"`
+	CheckName   string         `json:"check_name"`
+	Fingerprint string         `json:"fingerprint"`
+	Severity    string         `json:"severity"`
+	Location    gitlabLocation `json:"location"`
+}
+
+type gitlabLocation struct {
+	Path  string `json:"path"`
+	Lines struct {
+		Begin int `json:"begin"`
+	} `json:"lines"`
+}
+
+// GitLab writes a GitLab Code Quality report, the JSON array shown in the
+// merge request widget. Issues without a line are placed on line 1.
+type GitLab struct{}
+
+func (GitLab) Report(w io.Writer, run Run) error {
+	issues := make([]gitlabIssue, 0, len(run.Result.Issues))
+	prints := fingerprints(run.Result.Issues, run.Root)
+	for i, issue := range run.Result.Issues {
+		gi := gitlabIssue{
+			Description: Message(issue),
+			CheckName:   issue.Rule,
+			Fingerprint: prints[i],
+			Severity:    gitlabSeverity(issue.Severity),
+		}
+		gi.Location.Path = relPath(issue.File, run.Root)
+		gi.Location.Lines.Begin = max(issue.LineNum, 1)
+		issues = append(issues, gi)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(issues)
+}
+
+func gitlabSeverity(sev lint.Severity) string {
+	switch sev {
+	case lint.SeverityError:
+		return "major"
+	case lint.SeverityWarning:
+		return "minor"
+	}
+	return "info"
+}
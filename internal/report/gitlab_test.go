@@ -0,0 +1,71 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/rasalas/envlint/internal/lint"
+)
+
+func TestGitLab(t *testing.T) {
+	root := t.TempDir()
+	result := lint.Result{Issues: []lint.Issue{
+		{Rule: "invalid-url", Key: "API_URL", Severity: lint.SeverityError, Detail: "invalid URL format", File: filepath.Join(root, ".env"), LineNum: 4},
+		{Rule: "missing-key", Key: "API_KEY", Severity: lint.SeverityError, File: filepath.Join(root, ".env")},
+		{Rule: "extra-key", Key: "DEBUG", Severity: lint.SeverityWarning, File: filepath.Join(root, ".env"), LineNum: 2},
+		{Rule: "invalid-boolean", Key: "CACHE", Severity: lint.SeverityInfo, File: filepath.Join(root, ".env"), LineNum: 3},
+	}}
+
+	var buf bytes.Buffer
+	if err := (GitLab{}).Report(&buf, Run{Result: result, Root: root}); err != nil {
+		t.Fatal(err)
+	}
+	var out []gitlabIssue
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(out) != 4 {
+		t.Fatalf("expected 4 issues, got %d", len(out))
+	}
+
+	url := out[0]
+	if url.CheckName != "invalid-url" || url.Description != "API_URL: invalid URL format" || url.Severity != "major" {
+		t.Errorf("unexpected issue: %+v", url)
+	}
+	if url.Location.Path != ".env" || url.Location.Lines.Begin != 4 {
+		t.Errorf("unexpected location: %+v", url.Location)
+	}
+	if url.Fingerprint != Fingerprint(result.Issues[0], root, 0) {
+		t.Error("expected the shared fingerprint")
+	}
+	if out[1].Location.Lines.Begin != 1 {
+		t.Errorf("an issue without a line should begin at line 1, got %d", out[1].Location.Lines.Begin)
+	}
+	if out[2].Severity != "minor" || out[3].Severity != "info" {
+		t.Errorf("unexpected severities: %s, %s", out[2].Severity, out[3].Severity)
+	}
+
+	buf.Reset()
+	dup := lint.Result{Issues: []lint.Issue{
+		{Rule: "duplicate-key", Key: "A", Severity: lint.SeverityWarning, File: ".env", LineNum: 1},
+		{Rule: "duplicate-key", Key: "A", Severity: lint.SeverityWarning, File: ".env", LineNum: 2},
+	}}
+	if err := (GitLab{}).Report(&buf, Run{Result: dup}); err != nil {
+		t.Fatal(err)
+	}
+	out = nil
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(out) != 2 || out[0].Fingerprint == out[1].Fingerprint {
+		t.Errorf("each line of a duplicate key needs its own fingerprint: %+v", out)
+	}
+
+	buf.Reset()
+	(GitLab{}).Report(&buf, Run{})
+	if buf.String() != "[]\n" {
+		t.Errorf("expected an empty array for a clean run, got %q", buf.String())
+	}
+}
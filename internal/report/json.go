@@ -0,0 +1,19 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// JSON writes the envlint JSON output of the merged result.
+type JSON struct{}
+
+func (JSON) Report(w io.Writer, run Run) error {
+	out := run.Result.ToJSON()
+	if run.ShowSuppressed {
+		out.SuppressedIssues = run.Result.Suppressed
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
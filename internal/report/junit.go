@@ -0,0 +1,129 @@
+package report
+
+import (
+	"encoding/xml"
+	"io"
+
+	"github.com/rasalas/envlint/internal/lint"
+)
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Errors   int         `xml:"errors,attr"`
+	Cases    []junitCase `xml:"testcase"`
+	// SystemOut lists the baseline entries of the file that no longer
+	// occur.
+	SystemOut string `xml:"system-out,omitempty"`
+}
+
+type junitCase struct {
+	Name      string         `xml:"name,attr"`
+	ClassName string         `xml:"classname,attr"`
+	Failures  []junitFailure `xml:"failure"`
+	SystemOut string         `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// JUnit writes JUnit XML with one testsuite per env file and one testcase
+// per example key. Errors and warnings fail their testcase; info findings
+// are listed as output. Extra keys and issues without a key get their own
+// testcases, and the example gets a testsuite when it has issues. Baseline
+// entries that no longer occur go to the output of their testsuite.
+type JUnit struct{}
+
+func (JUnit) Report(w io.Writer, run Run) error {
+	out := junitSuites{Name: "envlint"}
+	for _, f := range run.Files {
+		r := f.Result.ForFile(f.Path)
+		out.Suites = append(out.Suites, newJUnitSuite(f.Path, run.ExampleKeys, r.Issues, r.Fixed, run.Root))
+	}
+	if r := run.Result.ForFile(run.Example); len(r.Issues) > 0 || len(r.Fixed) > 0 {
+		out.Suites = append(out.Suites, newJUnitSuite(run.Example, nil, r.Issues, r.Fixed, run.Root))
+	}
+	for _, s := range out.Suites {
+		out.Tests += s.Tests
+		out.Failures += s.Failures
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(out); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// newJUnitSuite builds the testsuite of one file from the keys it is
+// expected to have, its issues and its fixed baseline entries.
+func newJUnitSuite(path string, keys []string, issues []lint.Issue, fixed []lint.BaselineEntry, root string) junitSuite {
+	name := relPath(path, root)
+	suite := junitSuite{Name: name}
+	index := make(map[string]int)
+	caseFor := func(key string) *junitCase {
+		i, ok := index[key]
+		if !ok {
+			i = len(suite.Cases)
+			index[key] = i
+			caseName := key
+			if key == "" {
+				caseName = "(file)"
+			}
+			suite.Cases = append(suite.Cases, junitCase{Name: caseName, ClassName: name})
+		}
+		return &suite.Cases[i]
+	}
+	for _, key := range keys {
+		caseFor(key)
+	}
+
+	// Issues are sorted by line, so other keys follow in file order
+	for _, issue := range issues {
+		c := caseFor(issue.Key)
+		if issue.Severity == lint.SeverityInfo {
+			if c.SystemOut != "" {
+				c.SystemOut += "\n"
+			}
+			c.SystemOut += position(issue, root) + ": " + Message(issue)
+			continue
+		}
+		c.Failures = append(c.Failures, junitFailure{
+			Message: Message(issue),
+			Type:    issue.Rule,
+			Text:    string(issue.Severity) + " at " + position(issue, root),
+		})
+	}
+
+	for _, e := range fixed {
+		if suite.SystemOut != "" {
+			suite.SystemOut += "\n"
+		}
+		suite.SystemOut += fixedMessage(e)
+	}
+
+	suite.Tests = len(suite.Cases)
+	for _, c := range suite.Cases {
+		if len(c.Failures) > 0 {
+			suite.Failures++
+		}
+	}
+	return suite
+}
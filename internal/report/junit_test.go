@@ -0,0 +1,66 @@
+package report
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/rasalas/envlint/internal/lint"
+)
+
+func TestJUnit(t *testing.T) {
+	envResult := lint.Result{Issues: []lint.Issue{
+		{Rule: "syntax-error", Severity: lint.SeverityError, Detail: "expected KEY=VALUE", File: ".env", LineNum: 1},
+		{Rule: "missing-key", Key: "API_KEY", Severity: lint.SeverityError, File: ".env"},
+		{Rule: "extra-key", Key: "DEBUG", Severity: lint.SeverityWarning, File: ".env", LineNum: 4},
+		{Rule: "invalid-boolean", Key: "CACHE", Severity: lint.SeverityInfo, Detail: "expected boolean value", File: ".env", LineNum: 2},
+	}}
+	envResult.Fixed = []lint.BaselineEntry{{Rule: "invalid-url", Key: "API_URL", File: ".env", Count: 1}}
+	exampleIssue := lint.Issue{Rule: "secret-in-example", Key: "TOKEN", Severity: lint.SeverityError, File: ".env.example", LineNum: 3}
+	var combined lint.Result
+	combined.Merge(envResult)
+	combined.Merge(lint.Result{Issues: []lint.Issue{exampleIssue}})
+
+	run := Run{
+		Result:      combined,
+		Files:       []FileResult{{Path: ".env", Result: envResult}, {Path: ".env.local"}},
+		Example:     ".env.example",
+		ExampleKeys: []string{"API_KEY", "CACHE", "PORT"},
+	}
+	var buf bytes.Buffer
+	if err := (JUnit{}).Report(&buf, run); err != nil {
+		t.Fatal(err)
+	}
+	var out junitSuites
+	if err := xml.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, buf.String())
+	}
+
+	if len(out.Suites) != 3 {
+		t.Fatalf("expected suites for .env, .env.local and the example, got %d", len(out.Suites))
+	}
+	env := out.Suites[0]
+	var names []string
+	for _, c := range env.Cases {
+		names = append(names, c.Name)
+	}
+	if want := "API_KEY CACHE PORT (file) DEBUG"; strings.Join(names, " ") != want {
+		t.Errorf("expected testcases %q, got %q", want, strings.Join(names, " "))
+	}
+	if env.Tests != 5 || env.Failures != 3 {
+		t.Errorf("expected 5 tests and 3 failures, got %d and %d", env.Tests, env.Failures)
+	}
+	if cache := env.Cases[1]; len(cache.Failures) != 0 || cache.SystemOut == "" {
+		t.Errorf("info findings should be output, not failures: %+v", cache)
+	}
+	if !strings.Contains(env.SystemOut, "API_URL: invalid-url is fixed since the baseline") {
+		t.Errorf("fixed baseline entries should be suite output, got %q", env.SystemOut)
+	}
+	if local := out.Suites[1]; local.Tests != 3 || local.Failures != 0 {
+		t.Errorf("unexpected suite for a clean file: %+v", local)
+	}
+	if out.Tests != 9 || out.Failures != 4 {
+		t.Errorf("unexpected totals: %d tests, %d failures", out.Tests, out.Failures)
+	}
+}
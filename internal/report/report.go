@@ -0,0 +1,168 @@
+// Package report renders lint results in formats read by CI systems and
+// code review tools.
+package report
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strconv"
+
+	"github.com/rasalas/envlint/internal/lint"
+)
+
+// Reporter renders the result of a run in one output format.
+type Reporter interface {
+	Report(w io.Writer, run Run) error
+}
+
+// Run is the input of a reporter: the results of every linted env file
+// and the example they were checked against.
+type Run struct {
+	// Result merges the results of all files.
+	Result lint.Result
+	// Files holds the result of each env file, in the order linted.
+	Files []FileResult
+
+	Example     string
+	ExampleKeys []string // keys of the example, in file order
+
+	// Root is the directory paths are made relative to, usually the
+	// repository root. Empty keeps paths as given.
+	Root string
+	// ShowSuppressed lists suppressed issues where a format has no
+	// dedicated place for them.
+	ShowSuppressed bool
+}
+
+// FileResult is the result of one env file.
+type FileResult struct {
+	Path   string
+	Result lint.Result
+}
+
+// Paths returns the env files of the run followed by the example.
+func (run Run) Paths() []string {
+	paths := make([]string, 0, len(run.Files)+1)
+	for _, f := range run.Files {
+		paths = append(paths, f.Path)
+	}
+	return append(paths, run.Example)
+}
+
+var reporters = map[string]Reporter{
+	"json":       JSON{},
+	"sarif":      SARIF{},
+	"github":     GitHub{},
+	"gitlab":     GitLab{},
+	"junit":      JUnit{},
+	"checkstyle": Checkstyle{},
+	"markdown":   mustTemplate("markdown", markdownTemplate),
+}
+
+// Lookup returns the reporter of a format name.
+func Lookup(format string) (Reporter, bool) {
+	r, ok := reporters[format]
+	return r, ok
+}
+
+// Formats returns the names of all formats, sorted.
+func Formats() []string {
+	return slices.Sorted(maps.Keys(reporters))
+}
+
+// Message describes issue in one line for formats without the grouping
+// of the text output.
+func Message(issue lint.Issue) string {
+	text := issue.Detail
+	switch issue.Rule {
+	case "missing-key":
+		text = "missing in " + filepath.ToSlash(issue.File)
+		if issue.Detail != "" {
+			text += " (" + issue.Detail + ")"
+		}
+	case "extra-key":
+		text = "not in the example"
+	}
+	if text == "" {
+		if r, ok := lint.LookupRule(issue.Rule); ok {
+			text = r.Description()
+		}
+	}
+	if issue.Key == "" {
+		return text
+	}
+	return issue.Key + ": " + text
+}
+
+// fixedMessage describes a baseline entry that no longer occurs.
+func fixedMessage(e lint.BaselineEntry) string {
+	text := e.Rule + " is fixed since the baseline, run with --write-baseline to update it"
+	if e.Key == "" {
+		return text
+	}
+	return e.Key + ": " + text
+}
+
+// fixedIssue returns an issue that places a baseline entry in its file.
+func fixedIssue(e lint.BaselineEntry) lint.Issue {
+	return lint.Issue{Rule: e.Rule, Key: e.Key, File: filepath.FromSlash(e.File)}
+}
+
+// Fingerprint identifies an issue across runs by rule, key and file, so
+// it stays the same when lines move. The file is taken relative to root,
+// so the working directory does not matter. n is the number of earlier
+// issues with the same rule, key and file, such as the other lines of a
+// duplicate key, and tells them apart.
+func Fingerprint(issue lint.Issue, root string, n int) string {
+	id := issue.Rule + "\x00" + issue.Key + "\x00" + relPath(issue.File, root)
+	if n > 0 {
+		id += "\x00" + strconv.Itoa(n)
+	}
+	sum := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(sum[:16])
+}
+
+// fingerprints returns the fingerprint of each issue, numbering issues
+// with the same rule, key and file in the order given.
+func fingerprints(issues []lint.Issue, root string) []string {
+	type identity struct{ rule, key, file string }
+	seen := make(map[identity]int)
+	out := make([]string, len(issues))
+	for i, issue := range issues {
+		id := identity{issue.Rule, issue.Key, relPath(issue.File, root)}
+		out[i] = Fingerprint(issue, root, seen[id])
+		seen[id]++
+	}
+	return out
+}
+
+// position returns the file and line of issue as "path:line", with the
+// path relative to root.
+func position(issue lint.Issue, root string) string {
+	pos := relPath(issue.File, root)
+	if issue.LineNum > 0 {
+		pos += ":" + strconv.Itoa(issue.LineNum)
+	}
+	return pos
+}
+
+// relPath returns path relative to root with forward slashes, or path
+// itself if it is outside root.
+func relPath(path, root string) string {
+	if root == "" {
+		return filepath.ToSlash(path)
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil || !filepath.IsLocal(rel) {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
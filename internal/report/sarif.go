@@ -0,0 +1,195 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/rasalas/envlint/internal/lint"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	srcRoot      = "%SRCROOT%"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool               sarifTool                   `json:"tool"`
+	OriginalURIBaseIDs map[string]sarifArtifactLoc `json:"originalUriBaseIds,omitempty"`
+	Results            []sarifResult               `json:"results"`
+	Invocations        []sarifInvocation           `json:"invocations,omitempty"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful        bool                `json:"executionSuccessful"`
+	ToolExecutionNotifications []sarifNotification `json:"toolExecutionNotifications"`
+}
+
+type sarifNotification struct {
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string       `json:"id"`
+	ShortDescription     sarifMessage `json:"shortDescription"`
+	DefaultConfiguration struct {
+		Level string `json:"level"`
+	} `json:"defaultConfiguration"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID              string             `json:"ruleId"`
+	RuleIndex           int                `json:"ruleIndex"`
+	Level               string             `json:"level"`
+	Message             sarifMessage       `json:"message"`
+	Locations           []sarifLocation    `json:"locations"`
+	PartialFingerprints map[string]string  `json:"partialFingerprints"`
+	Suppressions        []sarifSuppression `json:"suppressions,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation struct {
+		ArtifactLocation sarifArtifactLoc `json:"artifactLocation"`
+		Region           *sarifRegion     `json:"region,omitempty"`
+	} `json:"physicalLocation"`
+}
+
+type sarifArtifactLoc struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+type sarifSuppression struct {
+	Kind string `json:"kind"`
+}
+
+// SARIF writes a SARIF 2.1.0 log with the rule catalogue and every issue.
+// Paths are relative to Run.Root so code scanning can place the results.
+// Issues silenced by envlint-disable comments are included as suppressed
+// results, and baseline entries that no longer occur as notifications of
+// the invocation.
+type SARIF struct{}
+
+func (SARIF) Report(w io.Writer, run Run) error {
+	result, root := run.Result, run.Root
+	rules := lint.Rules()
+	index := make(map[string]int, len(rules))
+	driver := sarifDriver{
+		Name:           "envlint",
+		InformationURI: "https://github.com/rasalas/envlint",
+		Rules:          make([]sarifRule, len(rules)),
+	}
+	for i, r := range rules {
+		index[r.ID()] = i
+		driver.Rules[i].ID = r.ID()
+		driver.Rules[i].ShortDescription.Text = r.Description()
+		driver.Rules[i].DefaultConfiguration.Level = sarifLevel(r.DefaultSeverity())
+	}
+
+	out := sarifRun{Tool: sarifTool{Driver: driver}, Results: []sarifResult{}}
+	if root != "" {
+		out.OriginalURIBaseIDs = map[string]sarifArtifactLoc{
+			srcRoot: {URI: rootURI(root)},
+		}
+	}
+	// Suppressed issues are numbered with the others so repeats that are
+	// partly suppressed keep distinct fingerprints
+	all := append(slices.Clip(result.Issues), result.Suppressed...)
+	prints := fingerprints(all, root)
+	add := func(issue lint.Issue, fingerprint string, suppressed bool) {
+		res := sarifResult{
+			RuleID:              issue.Rule,
+			RuleIndex:           index[issue.Rule],
+			Level:               sarifLevel(issue.Severity),
+			Message:             sarifMessage{Text: Message(issue)},
+			Locations:           []sarifLocation{location(issue, root)},
+			PartialFingerprints: map[string]string{"envlint/v1": fingerprint},
+		}
+		if suppressed {
+			res.Suppressions = []sarifSuppression{{Kind: "inSource"}}
+		}
+		out.Results = append(out.Results, res)
+	}
+	for i, issue := range all {
+		add(issue, prints[i], i >= len(result.Issues))
+	}
+	if len(result.Fixed) > 0 {
+		inv := sarifInvocation{ExecutionSuccessful: true}
+		for _, e := range result.Fixed {
+			inv.ToolExecutionNotifications = append(inv.ToolExecutionNotifications, sarifNotification{
+				Level:     "note",
+				Message:   sarifMessage{Text: fixedMessage(e)},
+				Locations: []sarifLocation{location(fixedIssue(e), root)},
+			})
+		}
+		out.Invocations = []sarifInvocation{inv}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{Schema: sarifSchema, Version: sarifVersion, Runs: []sarifRun{out}})
+}
+
+// rootURI returns the file URI of the directory root, with a trailing
+// slash as SARIF requires. Windows paths get the extra slash of
+// file:///C:/.
+func rootURI(root string) string {
+	u := url.URL{Scheme: "file", Path: "/" + strings.TrimPrefix(filepath.ToSlash(root), "/") + "/"}
+	return u.String()
+}
+
+func sarifLevel(sev lint.Severity) string {
+	switch sev {
+	case lint.SeverityError:
+		return "error"
+	case lint.SeverityWarning:
+		return "warning"
+	case lint.SeverityOff:
+		return "none"
+	}
+	return "note"
+}
+
+func location(issue lint.Issue, root string) sarifLocation {
+	var loc sarifLocation
+	loc.PhysicalLocation.ArtifactLocation = sarifArtifactLoc{URI: relPath(issue.File, root)}
+	if root != "" {
+		loc.PhysicalLocation.ArtifactLocation.URIBaseID = srcRoot
+	}
+	if issue.LineNum > 0 {
+		loc.PhysicalLocation.Region = &sarifRegion{StartLine: issue.LineNum, StartColumn: issue.Column}
+	}
+	return loc
+}
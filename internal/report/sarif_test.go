@@ -0,0 +1,128 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rasalas/envlint/internal/lint"
+)
+
+func TestSARIF(t *testing.T) {
+	root := t.TempDir()
+	result := lint.Result{
+		Issues: []lint.Issue{
+			{Rule: "invalid-port", Key: "PORT", Severity: lint.SeverityError, Detail: "must be 1-65535", File: filepath.Join(root, "app", ".env"), LineNum: 3, Column: 6},
+			{Rule: "missing-key", Key: "API_KEY", Severity: lint.SeverityError, File: filepath.Join(root, "app", ".env")},
+		},
+		Suppressed: []lint.Issue{
+			{Rule: "extra-key", Key: "DEBUG", Severity: lint.SeverityWarning, File: "/elsewhere/.env", LineNum: 1},
+		},
+		Fixed: []lint.BaselineEntry{{Rule: "invalid-url", Key: "API_URL", File: filepath.ToSlash(filepath.Join(root, "app", ".env")), Count: 1}},
+	}
+
+	var buf bytes.Buffer
+	if err := (SARIF{}).Report(&buf, Run{Result: result, Root: root}); err != nil {
+		t.Fatal(err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("unexpected log: %+v", log)
+	}
+
+	run := log.Runs[0]
+	if base := run.OriginalURIBaseIDs["%SRCROOT%"]; base.URI != rootURI(root) || !strings.HasPrefix(base.URI, "file:///") {
+		t.Errorf("unexpected originalUriBaseIds: %+v", run.OriginalURIBaseIDs)
+	}
+	if len(run.Tool.Driver.Rules) != len(lint.Rules()) {
+		t.Errorf("expected %d rules, got %d", len(lint.Rules()), len(run.Tool.Driver.Rules))
+	}
+	if len(run.Results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(run.Results))
+	}
+
+	port := run.Results[0]
+	if rule := run.Tool.Driver.Rules[port.RuleIndex]; rule.ID != "invalid-port" {
+		t.Errorf("ruleIndex points to %s", rule.ID)
+	}
+	loc := port.Locations[0].PhysicalLocation
+	if loc.ArtifactLocation.URI != "app/.env" || loc.ArtifactLocation.URIBaseID != "%SRCROOT%" {
+		t.Errorf("unexpected artifact location: %+v", loc.ArtifactLocation)
+	}
+	if loc.Region == nil || loc.Region.StartLine != 3 || loc.Region.StartColumn != 6 {
+		t.Errorf("unexpected region: %+v", loc.Region)
+	}
+	if port.Message.Text != "PORT: must be 1-65535" || port.PartialFingerprints["envlint/v1"] == "" {
+		t.Errorf("unexpected result: %+v", port)
+	}
+
+	if missing := run.Results[1]; missing.Locations[0].PhysicalLocation.Region != nil {
+		t.Error("an issue without a line should have no region")
+	}
+	suppressed := run.Results[2]
+	if len(suppressed.Suppressions) != 1 || suppressed.Locations[0].PhysicalLocation.ArtifactLocation.URI != "/elsewhere/.env" {
+		t.Errorf("unexpected suppressed result: %+v", suppressed)
+	}
+
+	if len(run.Invocations) != 1 || len(run.Invocations[0].ToolExecutionNotifications) != 1 {
+		t.Fatalf("expected the fixed baseline entry as a notification, got %+v", run.Invocations)
+	}
+	fixed := run.Invocations[0].ToolExecutionNotifications[0]
+	if fixed.Level != "note" || fixed.Locations[0].PhysicalLocation.ArtifactLocation.URI != "app/.env" {
+		t.Errorf("unexpected notification: %+v", fixed)
+	}
+}
+
+func TestRootURI(t *testing.T) {
+	tests := []struct{ root, want string }{
+		{"/home/ci/repo", "file:///home/ci/repo/"},
+		{"C:/work/repo", "file:///C:/work/repo/"},
+		{"/srv/my repo", "file:///srv/my%20repo/"},
+	}
+	for _, tt := range tests {
+		if got := rootURI(tt.root); got != tt.want {
+			t.Errorf("rootURI(%q) = %q, want %q", tt.root, got, tt.want)
+		}
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	a := lint.Issue{Rule: "extra-key", Key: "DEBUG", File: ".env", LineNum: 1}
+	b := a
+	b.LineNum = 7
+	if Fingerprint(a, "", 0) != Fingerprint(b, "", 0) {
+		t.Error("fingerprint should not depend on the line of a key")
+	}
+	b.File = ".env.local"
+	if Fingerprint(a, "", 0) == Fingerprint(b, "", 0) {
+		t.Error("fingerprint should depend on the file")
+	}
+
+	root := t.TempDir()
+	abs := lint.Issue{Rule: "extra-key", Key: "DEBUG", File: filepath.Join(root, ".env")}
+	if Fingerprint(abs, root, 0) != Fingerprint(a, "", 0) {
+		t.Error("fingerprint should use the path relative to root")
+	}
+
+	dups := []lint.Issue{
+		{Rule: "duplicate-key", Key: "A", File: ".env", LineNum: 1},
+		{Rule: "duplicate-key", Key: "A", File: ".env", LineNum: 2},
+		{Rule: "duplicate-key", Key: "B", File: ".env", LineNum: 3},
+	}
+	prints := fingerprints(dups, "")
+	if prints[0] == prints[1] {
+		t.Error("repeated issues should get distinct fingerprints")
+	}
+	if prints[0] != Fingerprint(dups[0], "", 0) || prints[2] != Fingerprint(dups[2], "", 0) {
+		t.Error("the first occurrence should keep the plain fingerprint")
+	}
+	dups[1].LineNum = 9
+	if fingerprints(dups, "")[1] != prints[1] {
+		t.Error("numbered fingerprints should not depend on the line")
+	}
+}
@@ -0,0 +1,186 @@
+package report
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/rasalas/envlint/internal/lint"
+)
+
+//go:embed templates/markdown.tmpl
+var markdownTemplate string
+
+// Template renders a run with a Go text/template. The template sees
+// lint.JSONOutput plus the issues grouped by file and by rule; see
+// TemplateData.
+type Template struct {
+	tmpl *template.Template
+}
+
+// NewTemplate parses text as a report template with the helper functions
+// of templateFuncs.
+func NewTemplate(name, text string) (Template, error) {
+	t, err := template.New(name).Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return Template{}, err
+	}
+	return Template{tmpl: t}, nil
+}
+
+func mustTemplate(name, text string) Template {
+	t, err := NewTemplate(name, text)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// TemplateData is the data a template is executed with.
+type TemplateData struct {
+	lint.JSONOutput
+
+	Example string
+	Files   []TemplateFile // env files in lint order, then the example
+	Rules   []TemplateRule // rules with issues, sorted by ID
+
+	// fingerprints holds the numbered fingerprint of every issue, as
+	// the other formats report it.
+	fingerprints map[lint.Issue]string
+}
+
+// fingerprint returns the fingerprint of an issue of the data.
+func (d TemplateData) fingerprint(issue lint.Issue) string {
+	if fp, ok := d.fingerprints[issue]; ok {
+		return fp
+	}
+	return Fingerprint(issue, "", 0)
+}
+
+// TemplateFile groups the issues of one file.
+type TemplateFile struct {
+	Path                    string
+	Errors, Warnings, Infos int
+	Issues                  []lint.Issue
+}
+
+// TemplateRule groups the issues of one rule.
+type TemplateRule struct {
+	ID          string
+	Description string
+	Issues      []lint.Issue
+}
+
+func (t Template) Report(w io.Writer, run Run) error {
+	data := newTemplateData(run)
+	tmpl, err := t.tmpl.Clone()
+	if err != nil {
+		return err
+	}
+	tmpl.Funcs(template.FuncMap{"fingerprint": data.fingerprint})
+	return tmpl.Execute(w, data)
+}
+
+// newTemplateData groups the issues of run. All paths, those of the issues
+// and fixed baseline entries included, are made relative to the root.
+func newTemplateData(run Run) TemplateData {
+	rel := func(issues []lint.Issue) []lint.Issue {
+		out := slices.Clone(issues)
+		for i := range out {
+			out[i].File = relPath(out[i].File, run.Root)
+		}
+		return out
+	}
+
+	data := TemplateData{JSONOutput: run.Result.ToJSON(), Example: relPath(run.Example, run.Root)}
+	all := slices.Concat(run.Result.Issues, run.Result.Suppressed)
+	data.fingerprints = make(map[lint.Issue]string, len(all))
+	for i, fp := range fingerprints(all, run.Root) {
+		issue := all[i]
+		issue.File = relPath(issue.File, run.Root)
+		data.fingerprints[issue] = fp
+	}
+	data.Issues = rel(data.Issues)
+	data.Fixed = slices.Clone(data.Fixed)
+	for i, e := range data.Fixed {
+		data.Fixed[i].File = relPath(fixedIssue(e).File, run.Root)
+	}
+	if run.ShowSuppressed {
+		data.SuppressedIssues = rel(run.Result.Suppressed)
+	}
+	for _, path := range run.Paths() {
+		r := run.Result.ForFile(path)
+		data.Files = append(data.Files, TemplateFile{
+			Path:     relPath(path, run.Root),
+			Errors:   r.ErrorCount(),
+			Warnings: r.WarnCount(),
+			Infos:    r.InfoCount(),
+			Issues:   rel(r.Issues),
+		})
+	}
+
+	byRule := make(map[string][]lint.Issue)
+	for _, issue := range data.Issues {
+		byRule[issue.Rule] = append(byRule[issue.Rule], issue)
+	}
+	for _, r := range lint.Rules() {
+		if issues := byRule[r.ID()]; len(issues) > 0 {
+			data.Rules = append(data.Rules, TemplateRule{ID: r.ID(), Description: r.Description(), Issues: issues})
+		}
+	}
+	return data
+}
+
+// templateFuncs are the helpers available to report templates.
+var templateFuncs = template.FuncMap{
+	"message":     Message,
+	"position":    func(issue lint.Issue) string { return position(issue, "") },
+	// Replaced per run by TemplateData.fingerprint, which numbers repeats
+	"fingerprint": func(issue lint.Issue) string { return Fingerprint(issue, "", 0) },
+	"severity": func(sev string, issues []lint.Issue) []lint.Issue {
+		return slices.DeleteFunc(slices.Clone(issues), func(i lint.Issue) bool { return string(i.Severity) != sev })
+	},
+	"icon": func(sev lint.Severity) string {
+		switch sev {
+		case lint.SeverityError:
+			return "❌"
+		case lint.SeverityWarning:
+			return "⚠️"
+		}
+		return "ℹ️"
+	},
+	"plural": func(n int, singular, plural string) string {
+		if n == 1 {
+			return singular
+		}
+		return plural
+	},
+	"upper":    strings.ToUpper,
+	"lower":    strings.ToLower,
+	"join":     strings.Join,
+	"replace":  strings.ReplaceAll,
+	"contains": strings.Contains,
+	"md":       escapeCell,
+	"csv": func(fields ...any) (string, error) {
+		var b strings.Builder
+		cw := csv.NewWriter(&b)
+		record := make([]string, len(fields))
+		for i, f := range fields {
+			record[i] = fmt.Sprint(f)
+		}
+		if err := cw.Write(record); err != nil {
+			return "", err
+		}
+		cw.Flush()
+		return b.String(), cw.Error()
+	},
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
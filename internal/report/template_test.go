@@ -0,0 +1,112 @@
+package report
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rasalas/envlint/internal/lint"
+)
+
+func templateRun(root string) Run {
+	envResult := lint.Result{Issues: []lint.Issue{
+		{Rule: "invalid-port", Key: "PORT", Severity: lint.SeverityError, Detail: "a|b", File: filepath.Join(root, ".env"), LineNum: 2},
+		{Rule: "extra-key", Key: "DEBUG", Severity: lint.SeverityWarning, File: filepath.Join(root, ".env"), LineNum: 3},
+		{Rule: "extra-key", Key: "TRACE", Severity: lint.SeverityWarning, File: filepath.Join(root, ".env"), LineNum: 4},
+	}}
+	envResult.Fixed = []lint.BaselineEntry{{Rule: "invalid-url", Key: "API_URL", File: filepath.ToSlash(filepath.Join(root, ".env")), Count: 1}}
+	return Run{
+		Result:  envResult,
+		Files:   []FileResult{{Path: filepath.Join(root, ".env"), Result: envResult}},
+		Example: filepath.Join(root, ".env.example"),
+		Root:    root,
+	}
+}
+
+func TestTemplate(t *testing.T) {
+	tmpl, err := NewTemplate("test", `{{range .Files}}{{.Path}} {{.Errors}}/{{.Warnings}}
+{{end}}{{range .Rules}}{{.ID}}: {{len .Issues}}
+{{end}}{{range severity "error" .Issues}}{{csv .Rule (position .) (message .)}}{{end}}{{.Errors}} {{plural .Errors "error" "errors"}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Report(&buf, templateRun(t.TempDir())); err != nil {
+		t.Fatal(err)
+	}
+	want := `.env 1/2
+.env.example 0/0
+extra-key: 2
+invalid-port: 1
+invalid-port,.env:2,PORT: a|b
+1 error`
+	if got := buf.String(); got != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+
+	if _, err := NewTemplate("bad", "{{.Issues"); err == nil {
+		t.Error("expected a parse error")
+	}
+	if _, err := NewTemplate("bad", "{{nope .Issues}}"); err == nil {
+		t.Error("expected an error for an unknown function")
+	}
+}
+
+func TestTemplateFingerprint(t *testing.T) {
+	root := t.TempDir()
+	result := lint.Result{Issues: []lint.Issue{
+		{Rule: "duplicate-key", Key: "A", Severity: lint.SeverityWarning, File: filepath.Join(root, ".env"), LineNum: 1},
+		{Rule: "duplicate-key", Key: "A", Severity: lint.SeverityWarning, File: filepath.Join(root, ".env"), LineNum: 2},
+	}}
+	tmpl, err := NewTemplate("test", `{{range .Issues}}{{fingerprint .}}
+{{end}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Report(&buf, Run{Result: result, Root: root}); err != nil {
+		t.Fatal(err)
+	}
+	got := strings.Fields(buf.String())
+	want := fingerprints(result.Issues, root)
+	if len(got) != 2 || got[0] == got[1] {
+		t.Fatalf("duplicate-key lines need distinct fingerprints, got %v", got)
+	}
+	if got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("template fingerprints %v differ from the other formats %v", got, want)
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	md, ok := Lookup("markdown")
+	if !ok {
+		t.Fatal("markdown format is not registered")
+	}
+
+	var buf bytes.Buffer
+	if err := md.Report(&buf, templateRun(t.TempDir())); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"❌ 1 error, 2 warnings, 0 info in 2 files",
+		"| `.env` | 1 | 2 | 0 |",
+		"### `.env`",
+		"| ❌ | `invalid-port` | 2 | PORT: a\\|b |",
+		"| ⚠️ | `extra-key` | 3 | DEBUG: not in the example |",
+		"- `.env` API_URL (`invalid-url`)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("markdown is missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "### `.env.example`") {
+		t.Error("files without issues should have no issue table")
+	}
+	if strings.HasSuffix(out, "\n\n") {
+		t.Error("markdown should not end with a blank line")
+	}
+}
@@ -0,0 +1,105 @@
+package secret
+
+import (
+	"math"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// tokenFormats are the formats of well-known credentials, checked in order.
+var tokenFormats = []struct {
+	kind    string
+	pattern *regexp.Regexp
+}{
+	{"private key", regexp.MustCompile(`-----BEGIN ([A-Z0-9]+ )*PRIVATE KEY( BLOCK)?-----`)},
+	{"AWS access key", regexp.MustCompile(`\b(AKIA|ASIA|ABIA|ACCA)[0-9A-Z]{16}\b`)},
+	{"GitHub token", regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+	{"Stripe live key", regexp.MustCompile(`\b[sr]k_live_[A-Za-z0-9]{16,}\b`)},
+	{"Slack token", regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}\b`)},
+	{"JWT", regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{8,}\.eyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}`)},
+}
+
+// Entropy thresholds in bits per character. Hex strings carry at most 4
+// bits per character, so they get a lower threshold.
+const (
+	minEntropyLength = 20
+	hexEntropy       = 3.0
+	tokenEntropy     = 4.0
+)
+
+// Scan reports whether val looks like a real credential and what kind:
+// a known token format, or a long random string on its own or in the path
+// or query of a URL.
+func Scan(val string) (kind string, ok bool) {
+	for _, f := range tokenFormats {
+		if f.pattern.MatchString(val) {
+			return f.kind, true
+		}
+	}
+	val = strings.TrimSpace(val)
+	if isRandom(val) {
+		return "high-entropy string", true
+	}
+	if hasRandomPart(val) {
+		return "high-entropy string in URL", true
+	}
+	return "", false
+}
+
+// hasRandomPart reports whether a path segment or query value of the URL
+// val is random, as in webhook, API key and presigned URLs.
+func hasRandomPart(val string) bool {
+	if !strings.Contains(val, "://") {
+		return false
+	}
+	u, err := url.Parse(val)
+	if err != nil {
+		return false
+	}
+	for _, seg := range strings.Split(u.Path, "/") {
+		if isRandom(seg) {
+			return true
+		}
+	}
+	for _, values := range u.Query() {
+		for _, v := range values {
+			if isRandom(v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isRandom reports whether val is a single long token whose Shannon
+// entropy suggests a generated key rather than a word, path or URL.
+func isRandom(val string) bool {
+	if len(val) < minEntropyLength || strings.ContainsAny(val, " \t\n") || strings.Contains(val, "://") {
+		return false
+	}
+	threshold := tokenEntropy
+	if strings.Trim(strings.ToLower(val), "0123456789abcdef") == "" {
+		threshold = hexEntropy
+	}
+	return Entropy(val) >= threshold
+}
+
+// Entropy returns the Shannon entropy of s in bits per character.
+func Entropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	counts := make(map[rune]int)
+	n := 0
+	for _, r := range s {
+		counts[r]++
+		n++
+	}
+	var h float64
+	for _, c := range counts {
+		p := float64(c) / float64(n)
+		h -= p * math.Log2(p)
+	}
+	return h
+}
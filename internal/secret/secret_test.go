@@ -0,0 +1,28 @@
+package secret
+
+import "testing"
+
+func TestLooksSecret(t *testing.T) {
+	tests := []struct {
+		key, val string
+		want     bool
+	}{
+		{"DB_PASSWORD", "hunter2", true},
+		{"STRIPE_API_KEY", "abc", true},
+		{"GITHUB_TOKEN", "", true},
+		{"DATABASE_URL", "postgres://app:s3cret@db:5432/app", true},
+		{"DATABASE_URL", "postgres://localhost:5432/app", false},
+		{"SLACK_WEBHOOK_URL", "https://hooks.slack.com/services/T0ABC1DEF/B0GHI2JKL/q8LmZx2kQ9", true},
+		{"NOTIFY_URL", "https://hooks.slack.com/services/T0ABC1DEF/B0GHI2JKL/q8LmZx2kQ9", true},
+		{"ALERTS_URL", "https://discord.com/api/webhooks/123/abc", true},
+		{"DOCS_URL", "https://docs.example.com/guides/hooks-and-events", false},
+		{"APP_PORT", "3000", false},
+		{"LOG_LEVEL", "info", false},
+		{"KEYBOARD_LAYOUT", "us", false},
+	}
+	for _, tt := range tests {
+		if got := LooksSecret(tt.key, tt.val); got != tt.want {
+			t.Errorf("LooksSecret(%s, %q) = %v, want %v", tt.key, tt.val, got, tt.want)
+		}
+	}
+}
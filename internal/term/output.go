@@ -39,13 +39,22 @@ func WarnDetail(key, detail string) {
 	fmt.Fprintf(W, "  %s!%s %s %s— %s%s\n", Yellow, Reset, key, Dim, detail, Reset)
 }
 
+// NoteDetail prints an info-level finding, with detail if not empty.
+func NoteDetail(key, detail string) {
+	if detail == "" {
+		fmt.Fprintf(W, "  %si%s %s\n", Primary, Reset, key)
+		return
+	}
+	fmt.Fprintf(W, "  %si%s %s %s— %s%s\n", Primary, Reset, key, Dim, detail, Reset)
+}
+
 // Info prints an informational line.
 func Info(msg string) {
 	fmt.Fprintf(W, "  %s%s%s\n", Dim, msg, Reset)
 }
 
 // Summary prints the final summary line.
-func Summary(valid, total, errors, warnings int) {
+func Summary(valid, total, errors, warnings, infos int) {
 	icon := Green + "✓" + Reset
 	if errors > 0 {
 		icon = Red + "✗" + Reset
@@ -57,6 +66,9 @@ func Summary(valid, total, errors, warnings int) {
 	if warnings > 0 {
 		fmt.Fprintf(W, " %s· %d warning(s)%s", Yellow, warnings, Reset)
 	}
+	if infos > 0 {
+		fmt.Fprintf(W, " %s· %d info%s", Dim, infos, Reset)
+	}
 	fmt.Fprintln(W)
 }
 
@@ -64,3 +76,27 @@ func Summary(valid, total, errors, warnings int) {
 func Title(envFile, exampleFile string) {
 	fmt.Fprintf(W, "\n  %senvlint%s %s· %s vs %s%s\n", Primary, Reset, Dim, envFile, exampleFile, Reset)
 }
+
+// Total prints the combined summary line across several env files.
+func Total(files, errors, warnings, infos int) {
+	icon := Green + "✓" + Reset
+	if errors > 0 {
+		icon = Red + "✗" + Reset
+	}
+	fmt.Fprintf(W, "  %s %d files checked", icon, files)
+	if errors > 0 {
+		fmt.Fprintf(W, " %s· %d error(s)%s", Red, errors, Reset)
+	}
+	if warnings > 0 {
+		fmt.Fprintf(W, " %s· %d warning(s)%s", Yellow, warnings, Reset)
+	}
+	if infos > 0 {
+		fmt.Fprintf(W, " %s· %d info%s", Dim, infos, Reset)
+	}
+	fmt.Fprintln(W)
+}
+
+// FileTitle prints the title line for a single file.
+func FileTitle(file string) {
+	fmt.Fprintf(W, "\n  %senvlint%s %s· %s%s\n", Primary, Reset, Dim, file, Reset)
+}